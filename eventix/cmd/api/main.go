@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"eventix/internal/entity"
 	"eventix/internal/handler"
@@ -10,6 +16,10 @@ import (
 	"eventix/internal/repository"
 	"eventix/internal/service"
 	"eventix/pkg/database"
+	"eventix/pkg/mailer"
+	"eventix/pkg/payment"
+	"eventix/pkg/ratelimit"
+	"eventix/pkg/utils"
 	"eventix/pkg/worker"
 
 	"github.com/gin-gonic/gin"
@@ -32,36 +42,181 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Load JWT signing keys up front so a bad key file fails at startup
+	if _, err := utils.TokenKeys(); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	if _, err := utils.TicketCodeSigner(); err != nil {
+		log.Fatalf("Failed to load ticket signing seed: %v", err)
+	}
+
 	// Run database migrations for all entities
 	if err := database.AutoMigrate(db,
+		&entity.Permission{},
+		&entity.Role{},
 		&entity.User{},
 		&entity.Event{},
+		&entity.TicketType{},
+		&entity.Venue{},
+		&entity.VenueSection{},
+		&entity.Seat{},
+		&entity.EventSeat{},
 		&entity.Order{},
+		&entity.OrderItem{},
+		&entity.PromoCode{},
+		&entity.PromoRedemption{},
+		&entity.WaitlistEntry{},
+		&entity.Hold{},
+		&entity.HoldItem{},
 		&entity.Ticket{},
+		&entity.TicketTransfer{},
+		&entity.Payment{},
+		&entity.Refund{},
+		&entity.PaymentWebhookEvent{},
+		&entity.IdempotencyKey{},
+		&entity.OutboxMessage{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
 	); err != nil {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 
 	// ==========================================================
-	// Step 3: Initialize Email Worker Channel and Goroutine
-	// ==========================================================
-	emailChan := make(chan worker.EmailJob, 100)
-	worker.StartEmailWorker(emailChan)
-
-	// ==========================================================
-	// Step 4: Dependency Injection - Repositories
+	// Step 3: Dependency Injection - Repositories
 	// ==========================================================
 	userRepo := repository.NewUserRepository(db)
 	eventRepo := repository.NewEventRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
 	ticketRepo := repository.NewTicketRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	ticketTypeRepo := repository.NewTicketTypeRepository(db)
+	promoCodeRepo := repository.NewPromoCodeRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	transferRepo := repository.NewTicketTransferRepository(db)
+	venueRepo := repository.NewVenueRepository(db)
+	eventSeatRepo := repository.NewEventSeatRepository(db)
+	holdRepo := repository.NewHoldRepository(db)
+
+	// ==========================================================
+	// Step 4: Dependency Injection - Services
+	// ==========================================================
+	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	// Seed built-in roles and permissions before serving requests
+	if err := roleService.EnsureDefaults(); err != nil {
+		log.Fatalf("Failed to seed roles: %v", err)
+	}
+	eventService := service.NewEventService(eventRepo, ticketTypeRepo, orderRepo, ticketRepo)
+	// Put events and orders from before ticket tiers onto a default tier
+	if err := eventService.EnsureTicketTypes(); err != nil {
+		log.Fatalf("Failed to migrate ticket types: %v", err)
+	}
+	// Payment providers, keyed by the payment_method sent by clients
+	fakeProvider := payment.NewFakeProvider(
+		payment.FakeMode(utils.GetEnv("FAKE_PAYMENT_MODE", string(payment.FakeModeSucceed))),
+		utils.GetEnvDuration("FAKE_PAYMENT_DELAY", 500*time.Millisecond),
+	)
+	paymentProviders := payment.NewRegistry()
+	paymentProviders.Register("credit_card", fakeProvider)
+	paymentProviders.Register("bank_transfer", fakeProvider)
+	paymentProviders.Register("e_wallet", fakeProvider)
+
+	paymentWindow := utils.GetEnvDuration("ORDER_PAYMENT_WINDOW", 15*time.Minute)
+	paymentTimeout := utils.GetEnvDuration("PAYMENT_TIMEOUT", 10*time.Second)
+	waitlistOfferWindow := utils.GetEnvDuration("WAITLIST_OFFER_WINDOW", 30*time.Minute)
+	// Tickets one user may get per event across orders, holds and waitlist
+	// offers; 0 disables
+	maxTicketsPerUser := utils.GetEnvInt("MAX_TICKETS_PER_USER", 0)
+	orderService := service.NewOrderService(
+		orderRepo, eventRepo, ticketRepo, ticketTypeRepo, promoCodeRepo, waitlistRepo, eventSeatRepo, holdRepo, userRepo,
+		paymentRepo, outboxRepo, paymentProviders, paymentWindow, paymentTimeout, waitlistOfferWindow, maxTicketsPerUser,
+	)
+	// Pending orders from before payment windows never expired; give them a deadline
+	if err := orderService.EnsureOrderExpiry(); err != nil {
+		log.Fatalf("Failed to migrate order expiry: %v", err)
+	}
+	refundPolicy, err := service.ParseRefundPolicy(utils.GetEnv("REFUND_POLICY", "7:100"))
+	if err != nil {
+		log.Fatalf("Invalid REFUND_POLICY: %v", err)
+	}
+	refundService := service.NewRefundService(
+		orderRepo, eventRepo, ticketRepo, ticketTypeRepo, refundRepo, paymentRepo, waitlistRepo, eventSeatRepo, holdRepo,
+		userRepo, outboxRepo, paymentProviders, refundPolicy, paymentTimeout, waitlistOfferWindow, maxTicketsPerUser,
+	)
+	holdService := service.NewHoldService(
+		holdRepo, orderRepo, eventRepo, ticketTypeRepo, promoCodeRepo, waitlistRepo, eventSeatRepo, userRepo, outboxRepo,
+		utils.GetEnvDuration("HOLD_WINDOW", 10*time.Minute), utils.GetEnvDuration("HOLD_EXTENSION", 5*time.Minute),
+		paymentWindow, waitlistOfferWindow, maxTicketsPerUser,
+	)
+	waitlistService := service.NewWaitlistService(
+		waitlistRepo, eventRepo, ticketTypeRepo, orderRepo, holdRepo, userRepo, outboxRepo, waitlistOfferWindow, maxTicketsPerUser,
+	)
+	ticketService := service.NewTicketService(ticketRepo, eventRepo)
+	if err := ticketService.EnsureTicketHolders(); err != nil {
+		log.Fatalf("Failed to migrate ticket holders: %v", err)
+	}
+	transferService := service.NewTicketTransferService(ticketRepo, transferRepo, userRepo, outboxRepo)
+	promoCodeService := service.NewPromoCodeService(promoCodeRepo, eventRepo)
+	documentService := service.NewTicketDocumentService(orderRepo, userRepo)
+	venueService := service.NewVenueService(venueRepo, eventSeatRepo, eventRepo, ticketTypeRepo, waitlistRepo)
 
 	// ==========================================================
-	// Step 5: Dependency Injection - Services
+	// Step 5: Start Background Workers
 	// ==========================================================
-	authService := service.NewAuthService(userRepo)
-	eventService := service.NewEventService(eventRepo)
-	orderService := service.NewOrderService(orderRepo, eventRepo, ticketRepo, emailChan)
+	emailMailer, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+
+	// Workers run until workerCtx is cancelled during shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Release tickets held by orders that were never paid
+	orderReaper := worker.NewOrderReaper(orderService, utils.GetEnvDuration("ORDER_REAPER_INTERVAL", time.Minute))
+	orderReaper.Start(workerCtx)
+
+	// Return tickets kept in carts that ran out
+	holdReaper := worker.NewHoldReaper(holdService, utils.GetEnvDuration("HOLD_REAPER_INTERVAL", 30*time.Second))
+	holdReaper.Start(workerCtx)
+
+	// Roll unclaimed waitlist offers over to the next person in line
+	waitlistWorker := worker.NewWaitlistWorker(waitlistService, utils.GetEnvDuration("WAITLIST_INTERVAL", time.Minute))
+	waitlistWorker.Start(workerCtx)
+
+	// Deliver notifications written to the outbox
+	outboxDispatcher := worker.NewOutboxDispatcher(outboxRepo, worker.OutboxDispatcherConfig{
+		Workers:       utils.GetEnvInt("OUTBOX_WORKERS", 4),
+		PollInterval:  utils.GetEnvDuration("OUTBOX_POLL_INTERVAL", 2*time.Second),
+		BatchSize:     utils.GetEnvInt("OUTBOX_BATCH_SIZE", 20),
+		Lease:         utils.GetEnvDuration("OUTBOX_LEASE", time.Minute),
+		MaxAttempts:   utils.GetEnvInt("OUTBOX_MAX_ATTEMPTS", 8),
+		RetryDelay:    utils.GetEnvDuration("OUTBOX_RETRY_DELAY", 30*time.Second),
+		MaxRetryDelay: utils.GetEnvDuration("OUTBOX_MAX_RETRY_DELAY", time.Hour),
+	})
+	outboxDispatcher.Handle(entity.OutboxTopicOrderConfirmation, worker.NewEmailJobHandler(emailMailer))
+	outboxDispatcher.Handle(entity.OutboxTopicWaitlistOffer, worker.NewWaitlistOfferJobHandler(emailMailer))
+	outboxDispatcher.Handle(entity.OutboxTopicTicketTransfer, worker.NewTicketTransferJobHandler(emailMailer))
+	outboxDispatcher.Handle(entity.OutboxTopicRefund, worker.NewRefundJobHandler(refundService))
+	outboxDispatcher.Start(workerCtx)
+
+	jobService := service.NewJobService(outboxRepo, outboxDispatcher, utils.GetEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour))
+
+	// Prune revoked and expired tokens, processed outbox messages and
+	// idempotency keys that can no longer be replayed
+	idempotencyKeyTTL := utils.GetEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	cleaner := worker.NewCleaner(utils.GetEnvDuration("TOKEN_CLEANUP_INTERVAL", time.Hour))
+	cleaner.Add("expired token(s)", authService.PurgeExpiredTokens)
+	cleaner.Add("processed outbox message(s)", jobService.PurgeProcessedJobs)
+	cleaner.Add("expired idempotency key(s)", func() (int64, error) {
+		return idempotencyRepo.PurgeExpired(time.Now().Add(-idempotencyKeyTTL))
+	})
+	cleaner.Start(workerCtx)
 
 	// ==========================================================
 	// Step 6: Dependency Injection - Handlers
@@ -70,12 +225,69 @@ func main() {
 	userHandler := handler.NewUserHandler()
 	eventHandler := handler.NewEventHandler(eventService)
 	orderHandler := handler.NewOrderHandler(orderService)
+	ticketHandler := handler.NewTicketHandler(ticketService)
+	// Gateways sign webhooks with this secret; without it every delivery
+	// would be rejected, so refuse to start rather than drop payments
+	webhooksEnabled := utils.GetEnvBool("PAYMENT_WEBHOOKS_ENABLED", true)
+	webhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if webhooksEnabled && webhookSecret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET must be set while payment webhooks are enabled (PAYMENT_WEBHOOKS_ENABLED)")
+	}
+	paymentHandler := handler.NewPaymentHandler(orderService, webhookSecret)
+	jobHandler := handler.NewJobHandler(jobService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	promoCodeHandler := handler.NewPromoCodeHandler(promoCodeService)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService, orderService)
+	refundHandler := handler.NewRefundHandler(refundService)
+	transferHandler := handler.NewTicketTransferHandler(transferService)
+	documentHandler := handler.NewTicketDocumentHandler(documentService)
+	venueHandler := handler.NewVenueHandler(venueService)
+	holdHandler := handler.NewHoldHandler(holdService)
 
 	// ==========================================================
 	// Step 7: Setup Gin Router and Routes
 	// ==========================================================
 	router := gin.Default()
 
+	// Client addresses are only taken from X-Forwarded-For when the request
+	// comes through one of these proxies; rate limits key on them
+	if err := router.SetTrustedProxies(utils.GetEnvList("TRUSTED_PROXIES", nil)); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Validates access tokens and rejects those revoked by logout
+	authMiddleware := middleware.AuthMiddleware(tokenRepo)
+
+	// Replays stored responses for retried booking and payment requests
+	idempotency := middleware.IdempotencyMiddleware(
+		idempotencyRepo,
+		idempotencyKeyTTL,
+		utils.GetEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+	)
+
+	// Token-bucket limits against credential stuffing and booking bots
+	rateLimitStore, err := ratelimit.NewStoreFromEnv(db)
+	if err != nil {
+		log.Fatalf("Failed to configure rate limit store: %v", err)
+	}
+	authLimit, err := ratelimit.LimitFromEnv("RATE_LIMIT_AUTH", "10/1m")
+	if err != nil {
+		log.Fatalf("Invalid rate limit: %v", err)
+	}
+	bookingLimit, err := ratelimit.LimitFromEnv("RATE_LIMIT_BOOKING", "20/1m,5")
+	if err != nil {
+		log.Fatalf("Invalid rate limit: %v", err)
+	}
+	bookingRouteLimit, err := ratelimit.LimitFromEnv("RATE_LIMIT_BOOKING_ROUTE", "300/1m,50")
+	if err != nil {
+		log.Fatalf("Invalid rate limit: %v", err)
+	}
+	authRateLimit := middleware.RateLimitMiddleware(rateLimitStore, "auth", authLimit, middleware.KeyByIP)
+	bookingRateLimit := middleware.RateLimitMiddleware(rateLimitStore, "booking", bookingLimit, middleware.KeyByUser)
+	// Runs after the per-user limit, so users over their own limit do not use
+	// up the tokens that cap a booking route's total throughput
+	bookingRouteRateLimit := middleware.RateLimitMiddleware(rateLimitStore, "booking-route", bookingRouteLimit, middleware.KeyByRoute)
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -84,19 +296,26 @@ func main() {
 		})
 	})
 
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	// API routes
 	api := router.Group("/api")
 	{
 		// Public authentication routes
+		// Only credential checks are limited per IP; refreshes from many users
+		// behind one NAT must not lock each other out
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", authRateLimit, authHandler.Register)
+			auth.POST("/login", authRateLimit, authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authMiddleware, authHandler.Logout)
 		}
 
 		// Protected user routes
 		users := api.Group("/users")
-		users.Use(middleware.AuthMiddleware())
+		users.Use(authMiddleware)
 		{
 			users.GET("/profile", userHandler.GetProfile)
 		}
@@ -107,28 +326,150 @@ func main() {
 			// Public event routes
 			events.GET("", eventHandler.GetAllEvents)
 			events.GET("/:id", eventHandler.GetEventByID)
+			events.GET("/:id/ticket-types", eventHandler.ListTicketTypes)
+			events.GET("/:id/seats", venueHandler.GetSeatMap)
 
 			// Protected booking route
-			events.POST("/:id/book", middleware.AuthMiddleware(), orderHandler.BookTickets)
+			events.POST("/:id/book", authMiddleware, idempotency, bookingRateLimit, bookingRouteRateLimit, orderHandler.BookTickets)
+			events.POST("/:id/holds", authMiddleware, idempotency, bookingRateLimit, bookingRouteRateLimit, holdHandler.CreateHold)
+			events.POST("/:id/waitlist", authMiddleware, waitlistHandler.JoinWaitlist)
 
-			// Admin-only event management routes
+			// Event management routes; organizers only reach their own events
 			adminEvents := events.Group("")
-			adminEvents.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+			adminEvents.Use(authMiddleware, middleware.RequirePermission(entity.PermissionEventsWrite))
 			{
 				adminEvents.POST("", eventHandler.CreateEvent)
 				adminEvents.PUT("/:id", eventHandler.UpdateEvent)
 				adminEvents.DELETE("/:id", eventHandler.DeleteEvent)
+				adminEvents.GET("/:id/orders", eventHandler.GetEventOrders)
+				adminEvents.GET("/:id/attendees", eventHandler.GetEventAttendees)
+				adminEvents.POST("/:id/ticket-types", eventHandler.CreateTicketType)
+				adminEvents.PUT("/:id/ticket-types/:ticketTypeId", eventHandler.UpdateTicketType)
+				adminEvents.DELETE("/:id/ticket-types/:ticketTypeId", eventHandler.DeleteTicketType)
+				adminEvents.POST("/:id/seating", venueHandler.ConfigureSeating)
 			}
+
+			// Gate scanning routes for door staff
+			scannerEvents := events.Group("/:id/checkin")
+			scannerEvents.Use(authMiddleware, middleware.RequirePermission(entity.PermissionTicketsCheckin))
+			{
+				scannerEvents.POST("", ticketHandler.CheckIn)
+				scannerEvents.GET("/stats", ticketHandler.GetCheckInStats)
+				// Offline verification: event public keys and revoked codes
+				scannerEvents.GET("/keys", ticketHandler.GetTicketKeys)
+				scannerEvents.GET("/revocations", ticketHandler.GetRevokedCodes)
+			}
+		}
+
+		// Venue seat maps; anyone may browse them
+		venues := api.Group("/venues")
+		{
+			venues.GET("", venueHandler.ListVenues)
+			venues.GET("/:id", venueHandler.GetVenue)
+		}
+
+		// Organizer dashboard
+		organizer := api.Group("/organizer")
+		organizer.Use(authMiddleware, middleware.RequirePermission(entity.PermissionEventsWrite))
+		{
+			organizer.GET("/events", eventHandler.GetMyEvents)
+		}
+
+		// Public payment gateway callbacks, authenticated by HMAC signature
+		if webhooksEnabled {
+			api.POST("/payments/webhook/:provider", paymentHandler.HandleWebhook)
 		}
 
 		// Protected order routes
 		orders := api.Group("/orders")
-		orders.Use(middleware.AuthMiddleware())
+		orders.Use(authMiddleware)
 		{
 			orders.GET("", orderHandler.GetUserOrders)
 			orders.GET("/:id", orderHandler.GetOrderByID)
-			orders.POST("/:id/pay", orderHandler.ProcessPayment)
+			orders.POST("/:id/pay", idempotency, orderHandler.ProcessPayment)
 			orders.POST("/:id/cancel", orderHandler.CancelOrder)
+			orders.POST("/:id/refund", idempotency, refundHandler.RefundOrder)
+			orders.GET("/:id/pdf", documentHandler.GetOrderPDF)
+			orders.GET("/:id/tickets/:ticketId/qr", documentHandler.GetTicketQR)
+		}
+
+		// Protected ticket routes; the holder, not the buyer, owns a ticket
+		tickets := api.Group("/tickets")
+		tickets.Use(authMiddleware)
+		{
+			tickets.GET("", ticketHandler.GetMyTickets)
+			tickets.POST("/:id/transfer", idempotency, transferHandler.InitiateTransfer)
+			tickets.GET("/:id/transfers", transferHandler.GetTicketHistory)
+		}
+
+		transfers := api.Group("/transfers")
+		transfers.Use(authMiddleware)
+		{
+			transfers.GET("", transferHandler.GetMyTransfers)
+			transfers.POST("/:id/accept", transferHandler.AcceptTransfer)
+			transfers.POST("/:id/decline", transferHandler.DeclineTransfer)
+			transfers.POST("/:id/cancel", transferHandler.CancelTransfer)
+		}
+
+		// Protected hold (cart) routes
+		holds := api.Group("/holds")
+		holds.Use(authMiddleware)
+		{
+			holds.GET("", holdHandler.GetMyHolds)
+			holds.GET("/:id", holdHandler.GetHold)
+			holds.POST("/:id/extend", holdHandler.ExtendHold)
+			holds.DELETE("/:id", holdHandler.ReleaseHold)
+			holds.POST("/:id/checkout", idempotency, bookingRateLimit, bookingRouteRateLimit, holdHandler.CheckoutHold)
+		}
+
+		// Protected waitlist routes
+		waitlist := api.Group("/waitlist")
+		waitlist.Use(authMiddleware)
+		{
+			waitlist.GET("", waitlistHandler.GetMyWaitlist)
+			waitlist.DELETE("/:id", waitlistHandler.LeaveWaitlist)
+			waitlist.POST("/:id/claim", idempotency, waitlistHandler.ClaimOffer)
+		}
+
+		// Back-office routes, each guarded by its own permission
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware)
+		{
+			jobs := admin.Group("/jobs")
+			jobs.Use(middleware.RequirePermission(entity.PermissionJobsManage))
+			{
+				jobs.GET("/stats", jobHandler.GetStats)
+				jobs.GET("/dead-letters", jobHandler.ListDeadLetters)
+				jobs.POST("/dead-letters/:id/requeue", jobHandler.RequeueDeadLetter)
+			}
+
+			admin.GET("/orders", middleware.RequirePermission(entity.PermissionOrdersReadAll), orderHandler.ListAllOrders)
+
+			admin.PUT("/events/:id/purchase-limit", middleware.RequirePermission(entity.PermissionEventsManageAll), eventHandler.SetPurchaseLimit)
+
+			roles := admin.Group("")
+			roles.Use(middleware.RequirePermission(entity.PermissionRolesManage))
+			{
+				roles.GET("/roles", roleHandler.ListRoles)
+				roles.GET("/permissions", roleHandler.ListPermissions)
+				roles.POST("/roles/:role/permissions", roleHandler.GrantPermission)
+				roles.DELETE("/roles/:role/permissions/:permission", roleHandler.RevokePermission)
+				roles.GET("/users/:id/roles", roleHandler.GetUserRoles)
+				roles.POST("/users/:id/roles", roleHandler.AssignRole)
+				roles.DELETE("/users/:id/roles/:role", roleHandler.RevokeRole)
+			}
+
+			promoCodes := admin.Group("/promo-codes")
+			promoCodes.Use(middleware.RequirePermission(entity.PermissionPromosManage))
+			{
+				promoCodes.GET("", promoCodeHandler.ListPromoCodes)
+				promoCodes.GET("/:id", promoCodeHandler.GetPromoCode)
+				promoCodes.POST("", promoCodeHandler.CreatePromoCode)
+				promoCodes.PUT("/:id", promoCodeHandler.UpdatePromoCode)
+				promoCodes.DELETE("/:id", promoCodeHandler.DeletePromoCode)
+			}
+
+			admin.POST("/venues", middleware.RequirePermission(entity.PermissionVenuesManage), venueHandler.CreateVenue)
 		}
 	}
 
@@ -140,8 +481,66 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Eventix API server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: utils.GetEnvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       utils.GetEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:      utils.GetEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       utils.GetEnvDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
 	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Eventix API server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// ==========================================================
+	// Step 9: Graceful Shutdown
+	// ==========================================================
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	select {
+	case err := <-serverErr:
+		log.Printf("Server error: %v", err)
+	case <-signalCtx.Done():
+		log.Println("Shutdown signal received")
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), utils.GetEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second))
+	defer cancelShutdown()
+
+	// Stop accepting connections and wait for in-flight requests
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server did not shut down cleanly: %v", err)
+	}
+
+	// Stop the workers; queued outbox messages stay in the database and
+	// claimed-but-unsent ones are released for the next instance
+	stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		orderReaper.Wait()
+		holdReaper.Wait()
+		waitlistWorker.Wait()
+		cleaner.Wait()
+		outboxDispatcher.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Println("Timed out waiting for background workers")
+	}
+
+	if err := database.Close(db); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+
+	log.Println("Eventix API server stopped")
 }
@@ -13,19 +13,34 @@ type Event struct {
 	TotalTickets     int       `gorm:"not null" json:"total_tickets"`
 	AvailableTickets int       `gorm:"not null" json:"available_tickets"`
 	Price            float64   `gorm:"type:decimal(10,2);not null" json:"price"`
+	OrganizerID      *uint     `gorm:"index" json:"organizer_id"`
+	VenueID          *uint     `gorm:"index" json:"venue_id,omitempty"`
 	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// MaxTicketsPerUser overrides the default per-user ticket limit for the
+	// event; 0 removes the limit
+	MaxTicketsPerUser *int `json:"max_tickets_per_user,omitempty"`
+
+	TicketTypes []TicketType `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"ticket_types,omitempty"`
 }
 
+// CreateEventInput creates an event with the given tiers, or with a single
+// General Admission tier of TotalTickets at Price when none are given. With
+// tiers, the event's capacity is the sum of their quantities and its price is
+// that of its cheapest tier.
 type CreateEventInput struct {
-	Title        string    `json:"title" binding:"required,min=3,max=200"`
-	Description  string    `json:"description"`
-	Date         time.Time `json:"date" binding:"required"`
-	Location     string    `json:"location" binding:"required"`
-	TotalTickets int       `json:"total_tickets" binding:"required,min=1"`
-	Price        float64   `json:"price" binding:"required,min=0"`
+	Title        string                  `json:"title" binding:"required,min=3,max=200"`
+	Description  string                  `json:"description"`
+	Date         time.Time               `json:"date" binding:"required"`
+	Location     string                  `json:"location" binding:"required"`
+	TotalTickets int                     `json:"total_tickets" binding:"omitempty,min=1"`
+	Price        float64                 `json:"price" binding:"required_without=TicketTypes,min=0"`
+	TicketTypes  []CreateTicketTypeInput `json:"ticket_types" binding:"omitempty,dive"`
 }
 
+// UpdateEventInput edits an event. TotalTickets and Price resize and reprice
+// the event's only tier; events with several tiers are edited tier by tier.
 type UpdateEventInput struct {
 	Title        string    `json:"title" binding:"omitempty,min=3,max=200"`
 	Description  string    `json:"description"`
@@ -35,11 +50,18 @@ type UpdateEventInput struct {
 	Price        float64   `json:"price" binding:"omitempty,min=0"`
 }
 
+// PurchaseLimitInput sets an event's per-user ticket limit. Null restores the
+// default limit and 0 lifts it.
+type PurchaseLimitInput struct {
+	MaxTicketsPerUser *int `json:"max_tickets_per_user" binding:"omitempty,min=0"`
+}
+
 type EventFilter struct {
-	Search   string
-	Location string
-	DateFrom time.Time
-	DateTo   time.Time
-	Page     int
-	PageSize int
+	Search      string
+	Location    string
+	OrganizerID uint
+	DateFrom    time.Time
+	DateTo      time.Time
+	Page        int
+	PageSize    int
 }
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive    HoldStatus = "ACTIVE"
+	HoldStatusConverted HoldStatus = "CONVERTED"
+	HoldStatusReleased  HoldStatus = "RELEASED"
+	HoldStatusExpired   HoldStatus = "EXPIRED"
+)
+
+// Hold is a shopping cart: tickets, and for seated tiers the chosen seats,
+// taken from inventory for a user until ExpiresAt while they fill in their
+// details. It can be extended once and is turned into a PENDING order at
+// checkout; holds that run out give their tickets back.
+type Hold struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	EventID     uint       `gorm:"not null;index" json:"event_id"`
+	Quantity    int        `gorm:"not null" json:"quantity"`
+	TotalAmount float64    `gorm:"type:decimal(10,2);not null" json:"total_amount"`
+	Status      HoldStatus `gorm:"type:varchar(20);not null;default:'ACTIVE';index:idx_hold_status_expiry" json:"status"`
+	ExpiresAt   time.Time  `gorm:"not null;index:idx_hold_status_expiry" json:"expires_at"`
+	ExtendedAt  *time.Time `json:"extended_at,omitempty"`
+	OrderID     *uint      `json:"order_id,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// SecondsRemaining counts down to ExpiresAt; only set while the hold is
+	// ACTIVE
+	SecondsRemaining int `gorm:"-" json:"seconds_remaining,omitempty"`
+
+	Event Event       `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Items []HoldItem  `gorm:"foreignKey:HoldID" json:"items,omitempty"`
+	Seats []EventSeat `gorm:"foreignKey:HoldID" json:"seats,omitempty"`
+}
+
+// HoldItem is one tier line of a hold, priced when the hold was made; the
+// order created at checkout keeps these prices.
+type HoldItem struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	HoldID         uint      `gorm:"not null;index" json:"hold_id"`
+	TicketTypeID   uint      `gorm:"not null;index" json:"ticket_type_id"`
+	TicketTypeName string    `gorm:"type:varchar(100);not null" json:"ticket_type_name"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	UnitPrice      float64   `gorm:"type:decimal(10,2);not null" json:"unit_price"`
+	Subtotal       float64   `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CreateHoldInput chooses tickets like a booking does: tier lines, a bare
+// quantity for events with a single tier, or seats.
+type CreateHoldInput struct {
+	Quantity int           `json:"qty" binding:"omitempty,min=1,max=10"`
+	Items    []BookingItem `json:"items" binding:"omitempty,max=10,dive"`
+	SeatIDs  []uint        `json:"seat_ids" binding:"omitempty,max=10"`
+}
+
+// CheckoutHoldInput is optional; a promo code is applied to the order when
+// the hold is checked out.
+type CheckoutHoldInput struct {
+	PromoCode string `json:"promo_code" binding:"omitempty,max=50"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyStatusProcessing IdempotencyStatus = "PROCESSING"
+	IdempotencyStatusCompleted  IdempotencyStatus = "COMPLETED"
+)
+
+// IdempotencyKey stores the first response produced for a client supplied
+// Idempotency-Key so that retries of the same request can be replayed.
+type IdempotencyKey struct {
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	UserID       uint              `gorm:"not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	Key          string            `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key" json:"key"`
+	RequestHash  string            `gorm:"type:varchar(64);not null" json:"request_hash"`
+	Status       IdempotencyStatus `gorm:"type:varchar(20);not null" json:"status"`
+	StatusCode   int               `json:"status_code"`
+	ResponseBody string            `gorm:"type:text" json:"response_body"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -10,6 +10,11 @@ const (
 	OrderStatusPending   OrderStatus = "PENDING"
 	OrderStatusPaid      OrderStatus = "PAID"
 	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusExpired   OrderStatus = "EXPIRED"
+	OrderStatusFailed    OrderStatus = "FAILED"
+
+	OrderStatusRefunded          OrderStatus = "REFUNDED"
+	OrderStatusPartiallyRefunded OrderStatus = "PARTIALLY_REFUNDED"
 )
 
 type Order struct {
@@ -19,18 +24,62 @@ type Order struct {
 	Quantity    int         `gorm:"not null" json:"quantity"`
 	TotalAmount float64     `gorm:"type:decimal(10,2);not null" json:"total_amount"`
 	Status      OrderStatus `gorm:"type:varchar(20);default:'PENDING'" json:"status"`
+	ExpiresAt   *time.Time  `gorm:"index" json:"expires_at,omitempty"`
 	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
 
-	Event   Event    `gorm:"foreignKey:EventID" json:"event,omitempty"`
-	User    User     `gorm:"foreignKey:UserID" json:"-"`
-	Tickets []Ticket `gorm:"foreignKey:OrderID" json:"tickets,omitempty"`
+	// Discount applied by a promo code; TotalAmount is already net of it
+	PromoCodeID    *uint   `gorm:"index" json:"promo_code_id,omitempty"`
+	PromoCode      string  `gorm:"type:varchar(50)" json:"promo_code,omitempty"`
+	DiscountAmount float64 `gorm:"type:decimal(10,2);not null;default:0" json:"discount_amount"`
+	// RefundedAmount is the sum of refunds issued, including ones still being
+	// paid out
+	RefundedAmount float64 `gorm:"type:decimal(10,2);not null;default:0" json:"refunded_amount"`
+
+	Event    Event       `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	User     User        `gorm:"foreignKey:UserID" json:"-"`
+	Items    []OrderItem `gorm:"foreignKey:OrderID" json:"items,omitempty"`
+	Tickets  []Ticket    `gorm:"foreignKey:OrderID" json:"tickets,omitempty"`
+	Payments []Payment   `gorm:"foreignKey:OrderID" json:"payments,omitempty"`
+	Refunds  []Refund    `gorm:"foreignKey:OrderID" json:"refunds,omitempty"`
+}
+
+// OrderItem is one tier line of an order. Name and price are copied from the
+// ticket type at booking time so later edits to the tier do not change it.
+type OrderItem struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrderID        uint      `gorm:"not null;index" json:"order_id"`
+	TicketTypeID   uint      `gorm:"not null;index" json:"ticket_type_id"`
+	TicketTypeName string    `gorm:"type:varchar(100);not null" json:"ticket_type_name"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	UnitPrice      float64   `gorm:"type:decimal(10,2);not null" json:"unit_price"`
+	Subtotal       float64   `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
+// BookingInput accepts either a list of tier lines or, for events with a
+// single tier, just a quantity. Seated tiers are booked by listing the seats
+// instead; their tiers and prices follow from the seats.
 type BookingInput struct {
-	Quantity int `json:"qty" binding:"required,min=1,max=10"`
+	Quantity int           `json:"qty" binding:"omitempty,min=1,max=10"`
+	Items    []BookingItem `json:"items" binding:"omitempty,max=10,dive"`
+	SeatIDs  []uint        `json:"seat_ids" binding:"omitempty,max=10"`
+	// PromoCode is optional and matched case-insensitively
+	PromoCode string `json:"promo_code" binding:"omitempty,max=50"`
+}
+
+type BookingItem struct {
+	TicketTypeID uint `json:"ticket_type_id" binding:"required"`
+	Quantity     int  `json:"qty" binding:"required,min=1,max=10"`
 }
 
 type PaymentInput struct {
 	PaymentMethod string `json:"payment_method" binding:"required"`
 }
+
+type OrderFilter struct {
+	Status   OrderStatus
+	EventID  uint
+	Page     int
+	PageSize int
+}
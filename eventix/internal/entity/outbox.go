@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending    OutboxStatus = "PENDING"
+	OutboxStatusProcessing OutboxStatus = "PROCESSING"
+	OutboxStatusDone       OutboxStatus = "DONE"
+	OutboxStatusDead       OutboxStatus = "DEAD"
+)
+
+// Outbox topics understood by the dispatcher.
+const (
+	OutboxTopicOrderConfirmation = "order_confirmation"
+	OutboxTopicWaitlistOffer     = "waitlist_offer"
+	OutboxTopicTicketTransfer    = "ticket_transfer"
+	OutboxTopicRefund            = "refund"
+)
+
+// OutboxMessage is a background job written in the same transaction as the
+// state change that caused it, so it is never lost and never sent for a
+// change that was rolled back.
+type OutboxMessage struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Topic       string       `gorm:"type:varchar(50);not null;index" json:"topic"`
+	Payload     string       `gorm:"type:text;not null" json:"payload"`
+	Status      OutboxStatus `gorm:"type:varchar(20);default:'PENDING';index:idx_outbox_status_available" json:"status"`
+	Attempts    int          `gorm:"not null;default:0" json:"attempts"`
+	AvailableAt time.Time    `gorm:"not null;index:idx_outbox_status_available" json:"available_at"`
+	LockedUntil *time.Time   `json:"locked_until,omitempty"`
+	ClaimToken  string       `gorm:"type:varchar(32)" json:"-"`
+	LastError   string       `gorm:"type:text" json:"last_error,omitempty"`
+	ProcessedAt *time.Time   `json:"processed_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OutboxStats combines queue depth from the database with the counters kept by
+// the dispatcher of this instance since it started.
+type OutboxStats struct {
+	Pending      int64  `json:"pending"`
+	Processing   int64  `json:"processing"`
+	Dead         int64  `json:"dead"`
+	Workers      int    `json:"workers"`
+	Processed    uint64 `json:"processed"`
+	Failed       uint64 `json:"failed"`
+	Retried      uint64 `json:"retried"`
+	DeadLettered uint64 `json:"dead_lettered"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "PENDING"
+	PaymentStatusAuthorized PaymentStatus = "AUTHORIZED"
+	PaymentStatusCaptured   PaymentStatus = "CAPTURED"
+	PaymentStatusDeclined   PaymentStatus = "DECLINED"
+	PaymentStatusFailed     PaymentStatus = "FAILED"
+	PaymentStatusRefunded   PaymentStatus = "REFUNDED"
+)
+
+// Payment records a single attempt to pay for an order with a provider.
+type Payment struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	OrderID       uint          `gorm:"not null;index" json:"order_id"`
+	Provider      string        `gorm:"type:varchar(50);not null" json:"provider"`
+	PaymentMethod string        `gorm:"type:varchar(50);not null" json:"payment_method"`
+	ProviderRef   string        `gorm:"type:varchar(100);index" json:"provider_ref,omitempty"`
+	Amount        float64       `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Status        PaymentStatus `gorm:"type:varchar(20);default:'PENDING'" json:"status"`
+	FailureReason string        `gorm:"type:text" json:"failure_reason,omitempty"`
+	LastEventAt   *time.Time    `json:"-"`
+	CreatedAt     time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PaymentWebhookEvent stores every webhook delivery that was accepted, keyed by
+// the provider's own event ID so replays are recognised and ignored.
+type PaymentWebhookEvent struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Provider        string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_webhook_provider_event" json:"provider"`
+	ProviderEventID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_webhook_provider_event" json:"provider_event_id"`
+	Type            string    `gorm:"type:varchar(50);not null" json:"type"`
+	OrderID         uint      `gorm:"index" json:"order_id"`
+	Payload         string    `gorm:"type:text" json:"payload"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
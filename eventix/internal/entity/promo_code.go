@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "PERCENTAGE"
+	DiscountTypeFixed      DiscountType = "FIXED"
+)
+
+type RedemptionStatus string
+
+const (
+	RedemptionStatusActive   RedemptionStatus = "ACTIVE"
+	RedemptionStatusReleased RedemptionStatus = "RELEASED"
+)
+
+// PromoCode is a discount customers enter at booking. Codes are stored upper
+// case and matched case-insensitively. Zero limits mean unlimited, and a nil
+// EventID makes the code valid for every event.
+type PromoCode struct {
+	ID              uint         `gorm:"primaryKey" json:"id"`
+	Code            string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Description     string       `gorm:"type:varchar(255)" json:"description"`
+	DiscountType    DiscountType `gorm:"type:varchar(20);not null" json:"discount_type"`
+	DiscountValue   float64      `gorm:"type:decimal(10,2);not null" json:"discount_value"`
+	EventID         *uint        `gorm:"index" json:"event_id,omitempty"`
+	ValidFrom       *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil      *time.Time   `json:"valid_until,omitempty"`
+	MaxRedemptions  int          `gorm:"not null;default:0" json:"max_redemptions"`
+	MaxPerUser      int          `gorm:"not null;default:0" json:"max_per_user"`
+	MinQuantity     int          `gorm:"not null;default:0" json:"min_quantity"`
+	RedemptionCount int          `gorm:"not null;default:0" json:"redemption_count"`
+	Active          bool         `gorm:"not null;default:true" json:"active"`
+	CreatedAt       time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Valid reports whether the code's validity window contains t.
+func (p *PromoCode) Valid(t time.Time) bool {
+	if p.ValidFrom != nil && t.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && t.After(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// Discount returns the amount taken off subtotal, never more than subtotal.
+func (p *PromoCode) Discount(subtotal float64) float64 {
+	discount := p.DiscountValue
+	if p.DiscountType == DiscountTypePercentage {
+		discount = math.Round(subtotal*p.DiscountValue) / 100
+	}
+	if discount > subtotal {
+		return subtotal
+	}
+	return discount
+}
+
+// PromoRedemption ties a use of a promo code to an order. Redemptions of
+// orders that are cancelled, expire or fail before payment are released and
+// no longer count towards the code's limits.
+type PromoRedemption struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	PromoCodeID uint             `gorm:"not null;index:idx_redemption_code_user" json:"promo_code_id"`
+	UserID      uint             `gorm:"not null;index:idx_redemption_code_user" json:"user_id"`
+	OrderID     uint             `gorm:"not null;uniqueIndex" json:"order_id"`
+	Discount    float64          `gorm:"type:decimal(10,2);not null" json:"discount"`
+	Status      RedemptionStatus `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"`
+	ReleasedAt  *time.Time       `json:"released_at,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type CreatePromoCodeInput struct {
+	Code           string       `json:"code" binding:"required,min=3,max=50,alphanumunicode"`
+	Description    string       `json:"description" binding:"max=255"`
+	DiscountType   DiscountType `json:"discount_type" binding:"required,oneof=PERCENTAGE FIXED"`
+	DiscountValue  float64      `json:"discount_value" binding:"required,gt=0"`
+	EventID        *uint        `json:"event_id"`
+	ValidFrom      *time.Time   `json:"valid_from"`
+	ValidUntil     *time.Time   `json:"valid_until"`
+	MaxRedemptions int          `json:"max_redemptions" binding:"min=0"`
+	MaxPerUser     int          `json:"max_per_user" binding:"min=0"`
+	MinQuantity    int          `json:"min_quantity" binding:"min=0"`
+}
+
+// UpdatePromoCodeInput changes only the fields that are present. The code and
+// its discount cannot change once created; issue a new code instead.
+type UpdatePromoCodeInput struct {
+	Description    *string    `json:"description" binding:"omitempty,max=255"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxRedemptions *int       `json:"max_redemptions" binding:"omitempty,min=0"`
+	MaxPerUser     *int       `json:"max_per_user" binding:"omitempty,min=0"`
+	MinQuantity    *int       `json:"min_quantity" binding:"omitempty,min=0"`
+	Active         *bool      `json:"active"`
+}
+
+type PromoCodeFilter struct {
+	EventID  uint
+	Page     int
+	PageSize int
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending    RefundStatus = "PENDING"
+	RefundStatusProcessing RefundStatus = "PROCESSING"
+	RefundStatusSucceeded  RefundStatus = "SUCCEEDED"
+)
+
+// Refund records money paid back for some or all tickets of an order. It is
+// PENDING until its payout starts and PROCESSING while the payment provider
+// may have been asked to pay it; FailureReason keeps the last payout error
+// while it is retried.
+type Refund struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	OrderID       uint         `gorm:"not null;index" json:"order_id"`
+	PaymentID     uint         `gorm:"not null;index" json:"payment_id"`
+	RequestedByID uint         `gorm:"not null" json:"requested_by_id"`
+	TicketCount   int          `gorm:"not null" json:"ticket_count"`
+	Percent       int          `gorm:"not null" json:"percent"`
+	Amount        float64      `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Reason        string       `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	Status        RefundStatus `gorm:"type:varchar(20);not null" json:"status"`
+	FailureReason string       `gorm:"type:text" json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// RefundInput selects the tickets to refund; an empty list refunds every
+// ticket of the order that has not been used or refunded yet.
+type RefundInput struct {
+	TicketIDs []uint `json:"ticket_ids"`
+	Reason    string `json:"reason" binding:"max=255"`
+	// OverridePolicy refunds in full regardless of the refund policy; it
+	// requires the orders:refund permission
+	OverridePolicy bool `json:"override_policy"`
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+)
+
+// Permissions checked by RequirePermission. Roles are named bundles of these.
+const (
+	PermissionEventsWrite     = "events:write"
+	PermissionEventsManageAll = "events:manage_all"
+	PermissionOrdersReadAll   = "orders:read_all"
+	PermissionOrdersRefund    = "orders:refund"
+	PermissionTicketsCheckin  = "tickets:checkin"
+	PermissionJobsManage      = "jobs:manage"
+	PermissionRolesManage     = "roles:manage"
+	PermissionPromosManage    = "promos:manage"
+	PermissionVenuesManage    = "venues:manage"
+)
+
+// Built-in roles seeded on startup.
+const (
+	RoleUser      = "user"
+	RoleOrganizer = "organizer"
+	RoleBoxOffice = "box_office"
+	RoleScanner   = "scanner"
+	RoleFinance   = "finance"
+	RoleAdmin     = "admin"
+)
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"type:varchar(64);uniqueIndex;not null" json:"name"`
+	Description string `gorm:"type:varchar(255)" json:"description"`
+}
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Name        string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string       `gorm:"type:varchar(255)" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// DefaultPermissions lists every permission the application checks.
+var DefaultPermissions = []Permission{
+	{Name: PermissionEventsWrite, Description: "Create events and manage your own"},
+	{Name: PermissionEventsManageAll, Description: "Manage every event and see its orders and attendees"},
+	{Name: PermissionOrdersReadAll, Description: "View orders placed by any user"},
+	{Name: PermissionOrdersRefund, Description: "Refund any order, optionally overriding the refund policy"},
+	{Name: PermissionTicketsCheckin, Description: "Scan tickets at the gate"},
+	{Name: PermissionJobsManage, Description: "Inspect and requeue background jobs"},
+	{Name: PermissionRolesManage, Description: "Assign and revoke user roles"},
+	{Name: PermissionPromosManage, Description: "Create and edit promo codes"},
+	{Name: PermissionVenuesManage, Description: "Create venues and their seat maps"},
+}
+
+// DefaultRoles are created if missing. Permissions of existing roles are left
+// alone, except that admin always holds every permission.
+var DefaultRoles = []Role{
+	{Name: RoleUser, Description: "Regular customer"},
+	{Name: RoleOrganizer, Description: "Manages events"},
+	{Name: RoleBoxOffice, Description: "Sells and checks tickets on site"},
+	{Name: RoleScanner, Description: "Door staff scanning tickets"},
+	{Name: RoleFinance, Description: "Read-only access to orders"},
+	{Name: RoleAdmin, Description: "Full access"},
+}
+
+var DefaultRolePermissions = map[string][]string{
+	RoleOrganizer: {PermissionEventsWrite},
+	RoleBoxOffice: {PermissionOrdersReadAll, PermissionTicketsCheckin},
+	RoleScanner:   {PermissionTicketsCheckin},
+	RoleFinance:   {PermissionOrdersReadAll},
+}
+
+type AssignRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type GrantPermissionInput struct {
+	Permission string `json:"permission" binding:"required"`
+}
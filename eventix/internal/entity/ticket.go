@@ -7,19 +7,60 @@ import (
 type TicketStatus string
 
 const (
-	TicketStatusValid TicketStatus = "VALID"
-	TicketStatusUsed  TicketStatus = "USED"
+	TicketStatusValid    TicketStatus = "VALID"
+	TicketStatusUsed     TicketStatus = "USED"
+	TicketStatusRefunded TicketStatus = "REFUNDED"
 )
 
+// Ticket is admission for one person. The holder starts out as the buyer and
+// changes when the ticket is transferred.
 type Ticket struct {
-	ID         uint         `gorm:"primaryKey" json:"id"`
-	OrderID    uint         `gorm:"not null;index" json:"order_id"`
-	EventID    uint         `gorm:"not null;index" json:"event_id"`
-	TicketCode string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"ticket_code"`
-	Status     TicketStatus `gorm:"type:varchar(20);default:'VALID'" json:"status"`
-	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	OrderID      uint         `gorm:"not null;index" json:"order_id"`
+	HolderID     *uint        `gorm:"index" json:"holder_id,omitempty"`
+	EventID      uint         `gorm:"not null;index" json:"event_id"`
+	TicketTypeID *uint        `gorm:"index" json:"ticket_type_id,omitempty"`
+	SeatID       *uint        `gorm:"index" json:"seat_id,omitempty"`
+	TicketCode   string       `gorm:"type:varchar(200);uniqueIndex;not null" json:"ticket_code"`
+	Status       TicketStatus `gorm:"type:varchar(20);default:'VALID'" json:"status"`
+	CheckedInAt  *time.Time   `json:"checked_in_at,omitempty"`
+	RefundID     *uint        `gorm:"index" json:"refund_id,omitempty"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
 
 	Order Order `gorm:"foreignKey:OrderID" json:"-"`
 	Event Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Seat  *Seat `gorm:"foreignKey:SeatID" json:"seat,omitempty"`
+}
+
+type CheckInInput struct {
+	TicketCode string `json:"ticket_code" binding:"required"`
+}
+
+// Attendee is a ticket holder as seen by the event's organizer. It carries no
+// ticket code, since a signed code admits whoever presents it.
+type Attendee struct {
+	TicketID    uint         `json:"ticket_id"`
+	Status      TicketStatus `json:"status"`
+	CheckedInAt *time.Time   `json:"checked_in_at,omitempty"`
+	OrderID     uint         `json:"order_id"`
+	UserID      uint         `json:"user_id"`
+	Name        string       `json:"name"`
+	Email       string       `json:"email"`
+}
+
+// RevokedTicketCode is a code scanners must turn away even though its
+// signature is valid, because the ticket was refunded or reissued.
+type RevokedTicketCode struct {
+	TicketID   uint      `json:"ticket_id"`
+	TicketCode string    `json:"ticket_code"`
+	Reason     string    `json:"reason"`
+	RevokedAt  time.Time `json:"revoked_at"`
+}
+
+type CheckInStats struct {
+	EventID      uint  `json:"event_id"`
+	TotalTickets int64 `json:"total_tickets"`
+	CheckedIn    int64 `json:"checked_in"`
+	Remaining    int64 `json:"remaining"`
 }
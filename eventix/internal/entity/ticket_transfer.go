@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "PENDING"
+	TransferStatusAccepted  TransferStatus = "ACCEPTED"
+	TransferStatusDeclined  TransferStatus = "DECLINED"
+	TransferStatusCancelled TransferStatus = "CANCELLED"
+)
+
+// TicketTransfer is one hand-over of a ticket from its holder to the user
+// with ToEmail. Accepted transfers reissue the ticket under a new code; the
+// code it had before is kept for support but never shown.
+type TicketTransfer struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	TicketID      uint           `gorm:"not null;index" json:"ticket_id"`
+	EventID       uint           `gorm:"not null;index" json:"event_id"`
+	FromUserID    uint           `gorm:"not null;index" json:"from_user_id"`
+	ToEmail       string         `gorm:"type:varchar(100);not null;index" json:"to_email"`
+	ToUserID      *uint          `json:"to_user_id,omitempty"`
+	Status        TransferStatus `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	OldTicketCode string         `gorm:"type:varchar(200)" json:"-"`
+	RespondedAt   *time.Time     `json:"responded_at,omitempty"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Event Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+}
+
+type TransferTicketInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
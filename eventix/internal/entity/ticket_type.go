@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+)
+
+// DefaultTicketTypeName is used for the tier created alongside events that do
+// not define their own, and for events that predate ticket tiers.
+const DefaultTicketTypeName = "General Admission"
+
+// TicketType is a priced tier of an event (e.g. Early Bird, Regular, VIP) with
+// its own inventory. The event's TotalTickets and AvailableTickets are kept at
+// the sums over its tiers. Seated tiers have one ticket per assigned seat and
+// are booked by seat rather than by quantity.
+type TicketType struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	EventID           uint       `gorm:"not null;uniqueIndex:idx_ticket_type_event_name" json:"event_id"`
+	Name              string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_ticket_type_event_name" json:"name"`
+	Description       string     `gorm:"type:text" json:"description"`
+	Price             float64    `gorm:"type:decimal(10,2);not null" json:"price"`
+	TotalQuantity     int        `gorm:"not null" json:"total_quantity"`
+	AvailableQuantity int        `gorm:"not null" json:"available_quantity"`
+	SalesStart        *time.Time `json:"sales_start,omitempty"`
+	SalesEnd          *time.Time `json:"sales_end,omitempty"`
+	MaxPerOrder       int        `gorm:"not null;default:0" json:"max_per_order"`
+	Seated            bool       `gorm:"not null;default:false" json:"seated"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OnSale reports whether the tier's sales window contains t.
+func (tt *TicketType) OnSale(t time.Time) bool {
+	if tt.SalesStart != nil && t.Before(*tt.SalesStart) {
+		return false
+	}
+	if tt.SalesEnd != nil && t.After(*tt.SalesEnd) {
+		return false
+	}
+	return true
+}
+
+type CreateTicketTypeInput struct {
+	Name          string     `json:"name" binding:"required,min=1,max=100"`
+	Description   string     `json:"description"`
+	Price         float64    `json:"price" binding:"min=0"`
+	TotalQuantity int        `json:"total_quantity" binding:"required,min=1"`
+	SalesStart    *time.Time `json:"sales_start"`
+	SalesEnd      *time.Time `json:"sales_end"`
+	MaxPerOrder   int        `json:"max_per_order" binding:"min=0"`
+}
+
+type UpdateTicketTypeInput struct {
+	Name          string     `json:"name" binding:"omitempty,min=1,max=100"`
+	Description   string     `json:"description"`
+	Price         *float64   `json:"price" binding:"omitempty,min=0"`
+	TotalQuantity int        `json:"total_quantity" binding:"omitempty,min=1"`
+	SalesStart    *time.Time `json:"sales_start"`
+	SalesEnd      *time.Time `json:"sales_end"`
+	MaxPerOrder   *int       `json:"max_per_order" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+)
+
+// RefreshToken is a long-lived, single-use credential. Every rotation creates
+// a new token in the same family; presenting an already rotated token means it
+// was stolen, so the whole family is revoked.
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID     string     `gorm:"type:varchar(64);not null;index" json:"family_id"`
+	TokenHash    string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// RevokedToken is a denylisted access token, kept until it would have expired.
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey" json:"jti"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
@@ -9,11 +9,26 @@ type User struct {
 	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
 	Email     string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	Password  string    `gorm:"type:varchar(255);not null" json:"-"`
-	Role      string    `gorm:"type:varchar(20);default:'user'" json:"role"`
+	Roles     []Role    `gorm:"many2many:user_roles" json:"roles,omitempty"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// Actor is the authenticated user a service call is made on behalf of.
+type Actor struct {
+	UserID      uint
+	Permissions []string
+}
+
+func (a Actor) Can(permission string) bool {
+	for _, granted := range a.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
 type RegisterInput struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
 	Email    string `json:"email" binding:"required,email"`
@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"time"
+)
+
+type SeatStatus string
+
+const (
+	SeatStatusAvailable SeatStatus = "AVAILABLE"
+	SeatStatusHeld      SeatStatus = "HELD"
+	SeatStatusSold      SeatStatus = "SOLD"
+)
+
+// Venue is a place with a fixed seat map that events can be held at.
+type Venue struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(200);not null" json:"name"`
+	Address   string    `gorm:"type:varchar(200)" json:"address"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Sections []VenueSection `gorm:"foreignKey:VenueID;constraint:OnDelete:CASCADE" json:"sections,omitempty"`
+}
+
+type VenueSection struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	VenueID   uint      `gorm:"not null;uniqueIndex:idx_venue_section_name" json:"venue_id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_venue_section_name" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Seats []Seat `gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE" json:"seats,omitempty"`
+}
+
+// Seat is one physical seat. Label is what gets printed on tickets, e.g.
+// "Orchestra, Row A, Seat 12".
+type Seat struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	SectionID  uint   `gorm:"not null;uniqueIndex:idx_seat_position" json:"section_id"`
+	Row        string `gorm:"type:varchar(10);not null;uniqueIndex:idx_seat_position" json:"row"`
+	Number     int    `gorm:"not null;uniqueIndex:idx_seat_position" json:"number"`
+	Label      string `gorm:"type:varchar(150);not null" json:"label"`
+	Accessible bool   `gorm:"not null;default:false" json:"accessible"`
+	Companion  bool   `gorm:"not null;default:false" json:"companion"`
+}
+
+// EventSeat is a venue seat on sale for one event, priced through the ticket
+// type its section was assigned to. A HELD seat belongs to either a hold
+// (HoldID) or a pending order (OrderID); OrderID is kept once it is sold.
+type EventSeat struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	EventID      uint       `gorm:"not null;uniqueIndex:idx_event_seat" json:"event_id"`
+	SeatID       uint       `gorm:"not null;uniqueIndex:idx_event_seat" json:"seat_id"`
+	TicketTypeID uint       `gorm:"not null;index" json:"ticket_type_id"`
+	Status       SeatStatus `gorm:"type:varchar(20);not null;default:'AVAILABLE'" json:"status"`
+	HoldID       *uint      `gorm:"index" json:"-"`
+	OrderID      *uint      `gorm:"index" json:"-"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Event      Event      `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"-"`
+	TicketType TicketType `gorm:"foreignKey:TicketTypeID;constraint:OnDelete:CASCADE" json:"-"`
+	Seat       Seat       `gorm:"foreignKey:SeatID" json:"-"`
+}
+
+// SeatAvailability is one seat of an event's seat map.
+type SeatAvailability struct {
+	SeatID       uint       `json:"seat_id"`
+	Section      string     `json:"section"`
+	Row          string     `json:"row"`
+	Number       int        `json:"number"`
+	Label        string     `json:"label"`
+	Accessible   bool       `json:"accessible"`
+	Companion    bool       `json:"companion"`
+	TicketTypeID uint       `json:"ticket_type_id"`
+	Price        float64    `json:"price"`
+	Status       SeatStatus `json:"status"`
+}
+
+// CreateVenueInput describes a seat map row by row. Seats of a row are
+// numbered from 1; Accessible and Companion list seat numbers within the row.
+type CreateVenueInput struct {
+	Name     string                    `json:"name" binding:"required,min=1,max=200"`
+	Address  string                    `json:"address" binding:"max=200"`
+	Sections []CreateVenueSectionInput `json:"sections" binding:"required,min=1,dive"`
+}
+
+type CreateVenueSectionInput struct {
+	Name string               `json:"name" binding:"required,min=1,max=100"`
+	Rows []CreateSeatRowInput `json:"rows" binding:"required,min=1,dive"`
+}
+
+type CreateSeatRowInput struct {
+	Label      string `json:"label" binding:"required,min=1,max=10"`
+	Seats      int    `json:"seats" binding:"required,min=1,max=500"`
+	Accessible []int  `json:"accessible"`
+	Companion  []int  `json:"companion"`
+}
+
+// ConfigureSeatingInput puts an event on sale seat by seat at a venue. Each
+// listed section is sold through the given ticket type of the event; sections
+// that are not listed stay closed.
+type ConfigureSeatingInput struct {
+	VenueID  uint                  `json:"venue_id" binding:"required"`
+	Sections []SectionPricingInput `json:"sections" binding:"required,min=1,dive"`
+}
+
+type SectionPricingInput struct {
+	SectionID    uint `json:"section_id" binding:"required"`
+	TicketTypeID uint `json:"ticket_type_id" binding:"required"`
+}
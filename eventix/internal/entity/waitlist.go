@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "WAITING"
+	WaitlistStatusOffered WaitlistStatus = "OFFERED"
+	WaitlistStatusClaimed WaitlistStatus = "CLAIMED"
+	WaitlistStatusExpired WaitlistStatus = "EXPIRED"
+	WaitlistStatusLeft    WaitlistStatus = "LEFT"
+)
+
+// WaitlistEntry is a user's place in line for a sold-out ticket type. When
+// tickets come back the first waiting entry whose quantity fits is OFFERED:
+// the tickets are held for it until OfferExpiresAt, and claiming the offer
+// turns them into a PENDING order.
+type WaitlistEntry struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	EventID        uint           `gorm:"not null;index" json:"event_id"`
+	TicketTypeID   uint           `gorm:"not null;index:idx_waitlist_ticket_type_status" json:"ticket_type_id"`
+	UserID         uint           `gorm:"not null;index" json:"user_id"`
+	Quantity       int            `gorm:"not null" json:"quantity"`
+	Status         WaitlistStatus `gorm:"type:varchar(20);not null;default:'WAITING';index:idx_waitlist_ticket_type_status" json:"status"`
+	OfferedAt      *time.Time     `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time     `gorm:"index" json:"offer_expires_at,omitempty"`
+	OrderID        *uint          `json:"order_id,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Position is the number of waiting entries ahead of this one plus one;
+	// only set while the entry is WAITING
+	Position int `gorm:"-" json:"position,omitempty"`
+
+	Event      Event      `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"event,omitempty"`
+	TicketType TicketType `gorm:"foreignKey:TicketTypeID;constraint:OnDelete:CASCADE" json:"ticket_type,omitempty"`
+}
+
+// JoinWaitlistInput works like a booking line; the ticket type may be omitted
+// for events with a single tier.
+type JoinWaitlistInput struct {
+	TicketTypeID uint `json:"ticket_type_id"`
+	Quantity     int  `json:"qty" binding:"required,min=1,max=10"`
+}
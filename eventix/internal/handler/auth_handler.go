@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"eventix/internal/entity"
+	"eventix/internal/middleware"
 	"eventix/internal/service"
+	"eventix/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,7 +51,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			"id":    user.ID,
 			"name":  user.Name,
 			"email": user.Email,
-			"role":  user.Role,
+			"roles": []string{entity.RoleUser},
 		},
 	})
 }
@@ -65,7 +67,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Login(&input)
+	tokens, err := h.authService.Login(&input)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -80,7 +82,85 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
+		"message":       "Login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 	})
 }
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var input entity.RefreshInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(input.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired refresh token",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to refresh token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Token refreshed successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var input entity.LogoutInput
+
+	// The body is optional; without a refresh token only the access token is revoked
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+	err := h.authService.Logout(userID, middleware.GetTokenID(c), middleware.GetTokenExpiresAt(c), input.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// JWKS publishes the public keys tokens are verified with so other services
+// can validate them without sharing a secret.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	keys, err := utils.TokenKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Signing keys are not available",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys.JWKS())
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"eventix/internal/entity"
+	"eventix/internal/middleware"
 	"eventix/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -31,8 +32,16 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventService.CreateEvent(&input)
+	event, err := h.eventService.CreateEvent(middleware.GetActor(c), &input)
 	if err != nil {
+		if errors.Is(err, service.ErrTicketTypeNameTaken) ||
+			errors.Is(err, service.ErrInvalidSalesWindow) ||
+			errors.Is(err, service.ErrEventCapacityRequired) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create event",
 		})
@@ -46,6 +55,43 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 }
 
 func (h *EventHandler) GetAllEvents(c *gin.Context) {
+	filter := parseEventFilter(c)
+
+	events, total, err := h.eventService.GetAllEvents(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch events",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"total":  total,
+		"page":   filter.Page,
+	})
+}
+
+// GetMyEvents lists the events organized by the authenticated user.
+func (h *EventHandler) GetMyEvents(c *gin.Context) {
+	filter := parseEventFilter(c)
+
+	events, total, err := h.eventService.GetOrganizerEvents(middleware.GetActor(c), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch events",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"total":  total,
+		"page":   filter.Page,
+	})
+}
+
+func parseEventFilter(c *gin.Context) entity.EventFilter {
 	filter := entity.EventFilter{
 		Search:   c.Query("search"),
 		Location: c.Query("location"),
@@ -75,19 +121,7 @@ func (h *EventHandler) GetAllEvents(c *gin.Context) {
 		}
 	}
 
-	events, total, err := h.eventService.GetAllEvents(filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch events",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-		"total":  total,
-		"page":   filter.Page,
-	})
+	return filter
 }
 
 func (h *EventHandler) GetEventByID(c *gin.Context) {
@@ -138,7 +172,7 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventService.UpdateEvent(uint(id), &input)
+	event, err := h.eventService.UpdateEvent(middleware.GetActor(c), uint(id), &input)
 	if err != nil {
 		if errors.Is(err, service.ErrEventNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -146,6 +180,20 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, service.ErrEventForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Not authorized to manage this event",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEventHasTicketTypes) ||
+			errors.Is(err, service.ErrTicketTypeBelowSold) ||
+			errors.Is(err, service.ErrSeatedTicketTypeResize) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to update event",
 		})
@@ -168,13 +216,19 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 		return
 	}
 
-	if err := h.eventService.DeleteEvent(uint(id)); err != nil {
+	if err := h.eventService.DeleteEvent(middleware.GetActor(c), uint(id)); err != nil {
 		if errors.Is(err, service.ErrEventNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Event not found",
 			})
 			return
 		}
+		if errors.Is(err, service.ErrEventForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Not authorized to manage this event",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to delete event",
 		})
@@ -185,3 +239,276 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 		"message": "Event deleted successfully",
 	})
 }
+
+func (h *EventHandler) SetPurchaseLimit(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.PurchaseLimitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	event, err := h.eventService.SetPurchaseLimit(uint(id), &input)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update purchase limit",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Purchase limit updated successfully",
+		"event":   event,
+	})
+}
+
+// GetEventOrders lists orders for an event the authenticated user organizes.
+func (h *EventHandler) GetEventOrders(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	filter := parseOrderFilter(c)
+	orders, total, err := h.eventService.GetEventOrders(middleware.GetActor(c), uint(id), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEventForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Not authorized to manage this event",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch orders",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"total":  total,
+		"page":   filter.Page,
+	})
+}
+
+// GetEventAttendees lists ticket holders of paid orders for an event the
+// authenticated user organizes.
+func (h *EventHandler) GetEventAttendees(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	attendees, err := h.eventService.GetEventAttendees(middleware.GetActor(c), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEventForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Not authorized to manage this event",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch attendees",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attendees": attendees,
+		"total":     len(attendees),
+	})
+}
+
+func (h *EventHandler) ListTicketTypes(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	ticketTypes, err := h.eventService.ListTicketTypes(uint(id))
+	if err != nil {
+		h.respondTicketTypeError(c, err, "Failed to fetch ticket types")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticket_types": ticketTypes,
+	})
+}
+
+func (h *EventHandler) CreateTicketType(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.CreateTicketTypeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ticketType, err := h.eventService.CreateTicketType(middleware.GetActor(c), uint(id), &input)
+	if err != nil {
+		h.respondTicketTypeError(c, err, "Failed to create ticket type")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Ticket type created successfully",
+		"ticket_type": ticketType,
+	})
+}
+
+func (h *EventHandler) UpdateTicketType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	ticketTypeID, err := strconv.ParseUint(c.Param("ticketTypeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ticket type ID",
+		})
+		return
+	}
+
+	var input entity.UpdateTicketTypeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ticketType, err := h.eventService.UpdateTicketType(middleware.GetActor(c), uint(id), uint(ticketTypeID), &input)
+	if err != nil {
+		h.respondTicketTypeError(c, err, "Failed to update ticket type")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Ticket type updated successfully",
+		"ticket_type": ticketType,
+	})
+}
+
+func (h *EventHandler) DeleteTicketType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	ticketTypeID, err := strconv.ParseUint(c.Param("ticketTypeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ticket type ID",
+		})
+		return
+	}
+
+	if err := h.eventService.DeleteTicketType(middleware.GetActor(c), uint(id), uint(ticketTypeID)); err != nil {
+		h.respondTicketTypeError(c, err, "Failed to delete ticket type")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ticket type deleted successfully",
+	})
+}
+
+func (h *EventHandler) respondTicketTypeError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrEventNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Event not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketTypeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ticket type not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrEventForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Not authorized to manage this event",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketTypeNameTaken) ||
+		errors.Is(err, service.ErrTicketTypeInUse) ||
+		errors.Is(err, service.ErrLastTicketType) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketTypeBelowSold) ||
+		errors.Is(err, service.ErrInvalidSalesWindow) ||
+		errors.Is(err, service.ErrSeatedTicketTypeResize) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
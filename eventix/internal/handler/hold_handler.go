@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	holdService service.HoldService
+}
+
+func NewHoldHandler(holdService service.HoldService) *HoldHandler {
+	return &HoldHandler{holdService: holdService}
+}
+
+func (h *HoldHandler) CreateHold(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.CreateHoldInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	hold, err := h.holdService.CreateHold(userID, uint(eventID), &input)
+	if err != nil {
+		h.respondError(c, err, "Failed to hold tickets")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tickets held",
+		"hold":    hold,
+	})
+}
+
+func (h *HoldHandler) GetMyHolds(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	holds, err := h.holdService.GetUserHolds(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch holds",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"holds": holds,
+	})
+}
+
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid hold ID",
+		})
+		return
+	}
+
+	hold, err := h.holdService.GetHold(userID, uint(holdID))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hold": hold,
+	})
+}
+
+func (h *HoldHandler) ExtendHold(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid hold ID",
+		})
+		return
+	}
+
+	hold, err := h.holdService.ExtendHold(userID, uint(holdID))
+	if err != nil {
+		h.respondError(c, err, "Failed to extend hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hold extended",
+		"hold":    hold,
+	})
+}
+
+func (h *HoldHandler) ReleaseHold(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid hold ID",
+		})
+		return
+	}
+
+	if err := h.holdService.ReleaseHold(userID, uint(holdID)); err != nil {
+		h.respondError(c, err, "Failed to release hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hold released",
+	})
+}
+
+func (h *HoldHandler) CheckoutHold(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid hold ID",
+		})
+		return
+	}
+
+	var input entity.CheckoutHoldInput
+
+	// The body is optional; it only carries a promo code
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	order, err := h.holdService.CheckoutHold(userID, uint(holdID), &input)
+	if err != nil {
+		h.respondError(c, err, "Failed to check out hold")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Order created, pay within the payment window",
+		"order":   order,
+	})
+}
+
+func (h *HoldHandler) respondError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrHoldNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Hold not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrEventNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Event not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketTypeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ticket type not found for this event",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSeatNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Seat is not on sale for this event",
+		})
+		return
+	}
+	if respondPurchaseLimit(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrHoldExpired) {
+		c.JSON(http.StatusGone, gin.H{
+			"error": "Hold has expired, please choose your tickets again",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrHoldNotActive) || errors.Is(err, service.ErrHoldAlreadyExtended) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInsufficientTickets) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Insufficient tickets available",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketTypeNotOnSale) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Ticket type is not on sale",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSeatUnavailable) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "One or more seats are no longer available",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidBooking) ||
+		errors.Is(err, service.ErrTicketTypeRequired) ||
+		errors.Is(err, service.ErrTooManyTickets) ||
+		errors.Is(err, service.ErrTicketTypeLimitExceeded) ||
+		errors.Is(err, service.ErrSeatSelectionRequired) ||
+		errors.Is(err, service.ErrSeatsWithQuantity) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Invalid booking",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPromoCodeExhausted) || errors.Is(err, service.ErrPromoCodeUserLimit) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Promo code cannot be used",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPromoCodeInvalid) ||
+		errors.Is(err, service.ErrPromoCodeNotValidNow) ||
+		errors.Is(err, service.ErrPromoCodeWrongEvent) ||
+		errors.Is(err, service.ErrPromoCodeMinQuantity) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Promo code cannot be used",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	jobService service.JobService
+}
+
+func NewJobHandler(jobService service.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+func (h *JobHandler) GetStats(c *gin.Context) {
+	stats, err := h.jobService.GetStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch job stats",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
+}
+
+func (h *JobHandler) ListDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+
+	jobs, total, err := h.jobService.ListDeadLetters(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch dead-letter jobs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"total": total,
+		"page":  page,
+	})
+}
+
+func (h *JobHandler) RequeueDeadLetter(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID",
+		})
+		return
+	}
+
+	if err := h.jobService.RequeueDeadLetter(uint(id)); err != nil {
+		if errors.Is(err, service.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Dead-letter job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to requeue job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Job requeued successfully",
+	})
+}
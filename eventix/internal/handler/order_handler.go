@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"eventix/internal/entity"
 	"eventix/internal/middleware"
 	"eventix/internal/service"
 
@@ -37,10 +38,7 @@ func (h *OrderHandler) BookTickets(c *gin.Context) {
 		return
 	}
 
-	var input struct {
-		Qty int `json:"qty" binding:"required,min=1,max=10"`
-	}
-
+	var input entity.BookingInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request payload",
@@ -49,7 +47,7 @@ func (h *OrderHandler) BookTickets(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.BookTickets(userID, uint(eventID), input.Qty)
+	order, err := h.orderService.BookTickets(userID, uint(eventID), &input)
 	if err != nil {
 		if errors.Is(err, service.ErrEventNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -59,7 +57,65 @@ func (h *OrderHandler) BookTickets(c *gin.Context) {
 		}
 		if errors.Is(err, service.ErrInsufficientTickets) {
 			c.JSON(http.StatusConflict, gin.H{
-				"error": "Insufficient tickets available",
+				"error":   "Insufficient tickets available",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Ticket type not found for this event",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotOnSale) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Ticket type is not on sale",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSeatNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Seat is not on sale for this event",
+			})
+			return
+		}
+		if respondPurchaseLimit(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrSeatUnavailable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "One or more seats are no longer available",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidBooking) ||
+			errors.Is(err, service.ErrTicketTypeRequired) ||
+			errors.Is(err, service.ErrTooManyTickets) ||
+			errors.Is(err, service.ErrTicketTypeLimitExceeded) ||
+			errors.Is(err, service.ErrSeatSelectionRequired) ||
+			errors.Is(err, service.ErrSeatsWithQuantity) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Invalid booking",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPromoCodeExhausted) || errors.Is(err, service.ErrPromoCodeUserLimit) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Promo code cannot be used",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPromoCodeInvalid) ||
+			errors.Is(err, service.ErrPromoCodeNotValidNow) ||
+			errors.Is(err, service.ErrPromoCodeWrongEvent) ||
+			errors.Is(err, service.ErrPromoCodeMinQuantity) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Promo code cannot be used",
+				"details": err.Error(),
 			})
 			return
 		}
@@ -93,7 +149,16 @@ func (h *OrderHandler) ProcessPayment(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.ProcessPayment(userID, uint(orderID))
+	var input entity.PaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	order, err := h.orderService.ProcessPayment(userID, uint(orderID), input.PaymentMethod)
 	if err != nil {
 		if errors.Is(err, service.ErrOrderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -119,12 +184,62 @@ func (h *OrderHandler) ProcessPayment(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, service.ErrOrderExpired) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Payment window has expired, please book again",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrOrderNotPending) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Order is no longer pending",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPaymentInProgress) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "A payment for this order is awaiting confirmation",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedPaymentMethod) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Unsupported payment method",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPaymentDeclined) {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error": "Payment was declined",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPaymentTimeout) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{
+				"error": "Payment provider did not respond in time",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPaymentFailed) {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error": "Payment provider error",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to process payment",
 		})
 		return
 	}
 
+	if order.Status == entity.OrderStatusPending {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Payment is being processed, awaiting confirmation",
+			"order":   order,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Payment successful",
 		"order":   order,
@@ -162,6 +277,12 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, service.ErrOrderNotPending) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Order is no longer pending",
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
@@ -213,7 +334,13 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.GetOrderByID(userID, uint(orderID))
+	// Staff with orders:read_all may view any order
+	var order *entity.Order
+	if middleware.HasPermission(c, entity.PermissionOrdersReadAll) {
+		order, err = h.orderService.GetAnyOrderByID(uint(orderID))
+	} else {
+		order, err = h.orderService.GetOrderByID(userID, uint(orderID))
+	}
 	if err != nil {
 		if errors.Is(err, service.ErrOrderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -237,3 +364,51 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 		"order": order,
 	})
 }
+
+func (h *OrderHandler) ListAllOrders(c *gin.Context) {
+	filter := parseOrderFilter(c)
+
+	orders, total, err := h.orderService.ListAllOrders(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch orders",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"total":  total,
+		"page":   filter.Page,
+	})
+}
+
+// respondPurchaseLimit answers requests rejected by the per-user ticket limit,
+// telling the user how many tickets they can still buy. It reports whether err
+// was such a rejection.
+func respondPurchaseLimit(c *gin.Context, err error) bool {
+	var limitErr *service.PurchaseLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":     "Per-user ticket limit reached",
+		"details":   err.Error(),
+		"limit":     limitErr.Limit,
+		"remaining": limitErr.Remaining,
+	})
+	return true
+}
+
+func parseOrderFilter(c *gin.Context) entity.OrderFilter {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	eventID, _ := strconv.ParseUint(c.Query("event_id"), 10, 32)
+
+	return entity.OrderFilter{
+		Status:   entity.OrderStatus(c.Query("status")),
+		EventID:  uint(eventID),
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
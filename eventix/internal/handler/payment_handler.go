@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"eventix/internal/service"
+	"eventix/pkg/payment"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentHandler struct {
+	orderService  service.OrderService
+	webhookSecret string
+}
+
+func NewPaymentHandler(orderService service.OrderService, webhookSecret string) *PaymentHandler {
+	return &PaymentHandler{
+		orderService:  orderService,
+		webhookSecret: webhookSecret,
+	}
+}
+
+// HandleWebhook receives asynchronous payment results from a gateway. The
+// signature is checked against the raw body before anything is parsed.
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read request body",
+		})
+		return
+	}
+
+	if err := payment.VerifySignature(h.webhookSecret, body, c.GetHeader(payment.SignatureHeader)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid webhook signature",
+		})
+		return
+	}
+
+	var event payment.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if event.ID == "" || event.Type == "" || event.OrderID == 0 || event.Reference == "" || event.OccurredAt.IsZero() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Webhook payload is missing required fields",
+		})
+		return
+	}
+
+	if err := h.orderService.HandlePaymentWebhook(c.Param("provider"), &event, body); err != nil {
+		if errors.Is(err, service.ErrDuplicateWebhook) {
+			c.JSON(http.StatusOK, gin.H{
+				"message": "Event already processed",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrUnknownPaymentProvider) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Unknown payment provider",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Order not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event processed",
+	})
+}
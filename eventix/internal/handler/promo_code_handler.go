@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromoCodeHandler struct {
+	promoCodeService service.PromoCodeService
+}
+
+func NewPromoCodeHandler(promoCodeService service.PromoCodeService) *PromoCodeHandler {
+	return &PromoCodeHandler{promoCodeService: promoCodeService}
+}
+
+func (h *PromoCodeHandler) ListPromoCodes(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	eventID, _ := strconv.ParseUint(c.Query("event_id"), 10, 32)
+
+	filter := entity.PromoCodeFilter{
+		EventID:  uint(eventID),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	promoCodes, total, err := h.promoCodeService.ListPromoCodes(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch promo codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promo_codes": promoCodes,
+		"total":       total,
+		"page":        filter.Page,
+	})
+}
+
+func (h *PromoCodeHandler) GetPromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid promo code ID",
+		})
+		return
+	}
+
+	promoCode, err := h.promoCodeService.GetPromoCode(uint(id))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promo_code": promoCode,
+	})
+}
+
+func (h *PromoCodeHandler) CreatePromoCode(c *gin.Context) {
+	var input entity.CreatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	promoCode, err := h.promoCodeService.CreatePromoCode(&input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create promo code")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Promo code created successfully",
+		"promo_code": promoCode,
+	})
+}
+
+func (h *PromoCodeHandler) UpdatePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid promo code ID",
+		})
+		return
+	}
+
+	var input entity.UpdatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	promoCode, err := h.promoCodeService.UpdatePromoCode(uint(id), &input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Promo code updated successfully",
+		"promo_code": promoCode,
+	})
+}
+
+func (h *PromoCodeHandler) DeletePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid promo code ID",
+		})
+		return
+	}
+
+	if err := h.promoCodeService.DeletePromoCode(uint(id)); err != nil {
+		h.respondError(c, err, "Failed to delete promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Promo code deleted successfully",
+	})
+}
+
+func (h *PromoCodeHandler) respondError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrPromoCodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Promo code not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrEventNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Event not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPromoCodeTaken) || errors.Is(err, service.ErrPromoCodeInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidDiscount) || errors.Is(err, service.ErrInvalidPromoWindow) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefundHandler struct {
+	refundService service.RefundService
+}
+
+func NewRefundHandler(refundService service.RefundService) *RefundHandler {
+	return &RefundHandler{refundService: refundService}
+}
+
+func (h *RefundHandler) RefundOrder(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid order ID",
+		})
+		return
+	}
+
+	var input entity.RefundInput
+
+	// The body is optional; without one every unused ticket is refunded
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	refund, err := h.refundService.RefundOrder(middleware.GetActor(c), uint(orderID), &input)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Order not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Not authorized to refund this order",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrOrderNotRefundable) ||
+			errors.Is(err, service.ErrNothingToRefund) ||
+			errors.Is(err, service.ErrTicketNotRefundable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketNotInOrder) || errors.Is(err, service.ErrRefundNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to refund order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Refund accepted, the money is being paid back",
+		"refund":  refund,
+	})
+}
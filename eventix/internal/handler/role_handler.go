@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.roleService.ListPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch permissions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"permissions": permissions,
+	})
+}
+
+func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.roleService.GetUserRoles(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entity.AssignRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.roleService.AssignRole(userID, input.Role); err != nil {
+		h.respondRoleError(c, err, "Failed to assign role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role assigned successfully",
+	})
+}
+
+func (h *RoleHandler) RevokeRole(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	actorID := middleware.GetUserID(c)
+	if err := h.roleService.RevokeRole(actorID, userID, c.Param("role")); err != nil {
+		h.respondRoleError(c, err, "Failed to revoke role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role revoked successfully",
+	})
+}
+
+func (h *RoleHandler) GrantPermission(c *gin.Context) {
+	var input entity.GrantPermissionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.roleService.GrantPermission(c.Param("role"), input.Permission); err != nil {
+		h.respondRoleError(c, err, "Failed to grant permission")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Permission granted successfully",
+	})
+}
+
+func (h *RoleHandler) RevokePermission(c *gin.Context) {
+	if err := h.roleService.RevokePermission(c.Param("role"), c.Param("permission")); err != nil {
+		h.respondRoleError(c, err, "Failed to revoke permission")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Permission revoked successfully",
+	})
+}
+
+func (h *RoleHandler) respondRoleError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRoleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Role not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRoleAlreadyAssigned) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "User already has this role",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRoleNotAssigned) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User does not have this role",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPermissionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Permission not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPermissionNotGranted) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Role does not have this permission",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrAdminRoleFixed) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Admin always holds every permission",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrLastRole) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "User must keep at least one role",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrCannotRevokeOwnRole) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot revoke your own role",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
+
+func parseUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return 0, false
+	}
+	return uint(userID), true
+}
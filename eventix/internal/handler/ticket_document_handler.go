@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+	"eventix/pkg/ticketdoc"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketDocumentHandler struct {
+	documentService service.TicketDocumentService
+}
+
+func NewTicketDocumentHandler(documentService service.TicketDocumentService) *TicketDocumentHandler {
+	return &TicketDocumentHandler{documentService: documentService}
+}
+
+func (h *TicketDocumentHandler) GetTicketQR(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid order ID",
+		})
+		return
+	}
+
+	ticketID, err := strconv.ParseUint(c.Param("ticketId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ticket ID",
+		})
+		return
+	}
+
+	png, err := h.documentService.RenderTicketQR(middleware.GetUserID(c), uint(orderID), uint(ticketID))
+	if err != nil {
+		h.respondError(c, err, "Failed to render QR code")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (h *TicketDocumentHandler) GetOrderPDF(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid order ID",
+		})
+		return
+	}
+
+	pdf, err := h.documentService.RenderOrderPDF(middleware.GetUserID(c), uint(orderID))
+	if err != nil {
+		h.respondError(c, err, "Failed to render tickets")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ticketdoc.FileName(uint(orderID))))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (h *TicketDocumentHandler) respondError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Order not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketNotInOrder) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ticket not found in this order",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Not authorized to access this order",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrOrderNotPaid) ||
+		errors.Is(err, service.ErrTicketRefunded) ||
+		errors.Is(err, service.ErrTicketTransferred) ||
+		errors.Is(err, service.ErrNoPrintableTickets) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
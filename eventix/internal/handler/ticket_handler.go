@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketHandler struct {
+	ticketService service.TicketService
+}
+
+func NewTicketHandler(ticketService service.TicketService) *TicketHandler {
+	return &TicketHandler{ticketService: ticketService}
+}
+
+func (h *TicketHandler) CheckIn(c *gin.Context) {
+	eventIDStr := c.Param("id")
+	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.CheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ticket, err := h.ticketService.CheckIn(uint(eventID), input.TicketCode)
+	if err != nil {
+		if errors.Is(err, service.ErrTicketNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":  "Unknown ticket code",
+				"result": "UNKNOWN_CODE",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketWrongEvent) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "Ticket does not belong to this event",
+				"result": "WRONG_EVENT",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketRefunded) {
+			c.JSON(http.StatusGone, gin.H{
+				"error":  "Ticket has been refunded",
+				"result": "REFUNDED",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrOrderNotPaid) {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":  "Order for this ticket has not been paid",
+				"result": "ORDER_NOT_PAID",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketAlreadyUsed) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":         "Ticket already used",
+				"result":        "ALREADY_USED",
+				"checked_in_at": ticket.CheckedInAt,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check in ticket",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ticket checked in successfully",
+		"result":  "ADMITTED",
+		"ticket":  ticket,
+	})
+}
+
+func (h *TicketHandler) GetCheckInStats(c *gin.Context) {
+	eventIDStr := c.Param("id")
+	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	stats, err := h.ticketService.GetCheckInStats(uint(eventID))
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch check-in stats",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+	})
+}
+
+func (h *TicketHandler) GetMyTickets(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	tickets, err := h.ticketService.GetUserTickets(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch tickets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tickets": tickets,
+	})
+}
+
+func (h *TicketHandler) GetTicketKeys(c *gin.Context) {
+	eventIDStr := c.Param("id")
+	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	keys, err := h.ticketService.GetTicketKeys(uint(eventID))
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch ticket keys",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *TicketHandler) GetRevokedCodes(c *gin.Context) {
+	eventIDStr := c.Param("id")
+	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	revoked, err := h.ticketService.GetRevokedCodes(uint(eventID))
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch revoked ticket codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event_id":     eventID,
+		"revoked":      revoked,
+		"generated_at": time.Now(),
+	})
+}
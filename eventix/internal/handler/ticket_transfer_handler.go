@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketTransferHandler struct {
+	transferService service.TicketTransferService
+}
+
+func NewTicketTransferHandler(transferService service.TicketTransferService) *TicketTransferHandler {
+	return &TicketTransferHandler{transferService: transferService}
+}
+
+func (h *TicketTransferHandler) InitiateTransfer(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ticket ID",
+		})
+		return
+	}
+
+	var input entity.TransferTicketInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.transferService.InitiateTransfer(middleware.GetUserID(c), uint(ticketID), &input)
+	if err != nil {
+		h.respondError(c, err, "Failed to transfer ticket")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Transfer sent, the recipient must accept it",
+		"transfer": transfer,
+	})
+}
+
+func (h *TicketTransferHandler) GetTicketHistory(c *gin.Context) {
+	ticketID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ticket ID",
+		})
+		return
+	}
+
+	transfers, err := h.transferService.GetTicketHistory(middleware.GetActor(c), uint(ticketID))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch transfer history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfers": transfers,
+	})
+}
+
+func (h *TicketTransferHandler) GetMyTransfers(c *gin.Context) {
+	transfers, err := h.transferService.GetUserTransfers(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch transfers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfers": transfers,
+	})
+}
+
+func (h *TicketTransferHandler) AcceptTransfer(c *gin.Context) {
+	transferID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transfer ID",
+		})
+		return
+	}
+
+	ticket, err := h.transferService.AcceptTransfer(middleware.GetUserID(c), uint(transferID))
+	if err != nil {
+		h.respondError(c, err, "Failed to accept transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transfer accepted, the ticket has been reissued to you",
+		"ticket":  ticket,
+	})
+}
+
+func (h *TicketTransferHandler) DeclineTransfer(c *gin.Context) {
+	transferID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transfer ID",
+		})
+		return
+	}
+
+	if err := h.transferService.DeclineTransfer(middleware.GetUserID(c), uint(transferID)); err != nil {
+		h.respondError(c, err, "Failed to decline transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transfer declined",
+	})
+}
+
+func (h *TicketTransferHandler) CancelTransfer(c *gin.Context) {
+	transferID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transfer ID",
+		})
+		return
+	}
+
+	if err := h.transferService.CancelTransfer(middleware.GetUserID(c), uint(transferID)); err != nil {
+		h.respondError(c, err, "Failed to cancel transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transfer cancelled",
+	})
+}
+
+func (h *TicketTransferHandler) respondError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ticket not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTransferNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Transfer not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTransferToSelf) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTransferPending) ||
+		errors.Is(err, service.ErrTransferNotPending) ||
+		errors.Is(err, service.ErrTicketAlreadyUsed) ||
+		errors.Is(err, service.ErrTicketRefunded) ||
+		errors.Is(err, service.ErrOrderNotPaid) ||
+		errors.Is(err, service.ErrEventStarted) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
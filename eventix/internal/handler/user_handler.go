@@ -16,13 +16,13 @@ func NewUserHandler() *UserHandler {
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
-	role := middleware.GetUserRole(c)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile retrieved successfully",
 		"user": gin.H{
-			"id":   userID,
-			"role": role,
+			"id":          userID,
+			"roles":       middleware.GetUserRoles(c),
+			"permissions": middleware.GetUserPermissions(c),
 		},
 	})
 }
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VenueHandler struct {
+	venueService service.VenueService
+}
+
+func NewVenueHandler(venueService service.VenueService) *VenueHandler {
+	return &VenueHandler{venueService: venueService}
+}
+
+func (h *VenueHandler) ListVenues(c *gin.Context) {
+	venues, err := h.venueService.ListVenues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch venues",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venues": venues,
+	})
+}
+
+func (h *VenueHandler) GetVenue(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid venue ID",
+		})
+		return
+	}
+
+	venue, err := h.venueService.GetVenue(uint(id))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch venue")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venue": venue,
+	})
+}
+
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	var input entity.CreateVenueInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	venue, err := h.venueService.CreateVenue(&input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create venue")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Venue created successfully",
+		"venue":   venue,
+	})
+}
+
+func (h *VenueHandler) ConfigureSeating(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.ConfigureSeatingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	seats, err := h.venueService.ConfigureSeating(middleware.GetActor(c), uint(eventID), &input)
+	if err != nil {
+		h.respondError(c, err, "Failed to configure seating")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Seats are on sale",
+		"seats":   seats,
+	})
+}
+
+func (h *VenueHandler) GetSeatMap(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	seats, err := h.venueService.GetSeatMap(uint(eventID))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch seat map")
+		return
+	}
+
+	available := 0
+	for _, seat := range seats {
+		if seat.Status == entity.SeatStatusAvailable {
+			available++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event_id":        eventID,
+		"seats":           seats,
+		"total_seats":     len(seats),
+		"available_seats": available,
+	})
+}
+
+func (h *VenueHandler) respondError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, service.ErrVenueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Venue not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrEventNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Event not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSeatingNotConfigured) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Event has no seat map",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrEventForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Not authorized to manage this event",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSeatingConfigured) || errors.Is(err, service.ErrTicketTypeInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidSeatLayout) ||
+		errors.Is(err, service.ErrSectionNotInVenue) ||
+		errors.Is(err, service.ErrInvalidSeatingPlan) ||
+		errors.Is(err, service.ErrTicketTypeNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventix/internal/entity"
+	"eventix/internal/middleware"
+	"eventix/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	waitlistService service.WaitlistService
+	orderService    service.OrderService
+}
+
+func NewWaitlistHandler(waitlistService service.WaitlistService, orderService service.OrderService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+		orderService:    orderService,
+	}
+}
+
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return
+	}
+
+	var input entity.JoinWaitlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.waitlistService.JoinWaitlist(userID, uint(eventID), &input)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Ticket type not found for this event",
+			})
+			return
+		}
+		if respondPurchaseLimit(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrAlreadyOnWaitlist) ||
+			errors.Is(err, service.ErrTicketsStillAvailable) ||
+			errors.Is(err, service.ErrTicketTypeNotOnSale) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeRequired) ||
+			errors.Is(err, service.ErrTicketTypeLimitExceeded) ||
+			errors.Is(err, service.ErrSeatedTicketType) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to join waitlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Joined the waitlist",
+		"entry":   entry,
+	})
+}
+
+func (h *WaitlistHandler) GetMyWaitlist(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	entries, err := h.waitlistService.GetUserWaitlist(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch waitlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
+
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid waitlist entry ID",
+		})
+		return
+	}
+
+	if err := h.waitlistService.LeaveWaitlist(userID, uint(entryID)); err != nil {
+		if errors.Is(err, service.ErrWaitlistEntryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Waitlist entry not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrWaitlistNotActive) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to leave waitlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Left the waitlist",
+	})
+}
+
+func (h *WaitlistHandler) ClaimOffer(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid waitlist entry ID",
+		})
+		return
+	}
+
+	order, err := h.orderService.ClaimWaitlistOffer(userID, uint(entryID))
+	if err != nil {
+		if errors.Is(err, service.ErrWaitlistEntryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Waitlist entry not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrNoWaitlistOffer) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrWaitlistOfferExpired) {
+			c.JSON(http.StatusGone, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotOnSale) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Ticket type is not on sale",
+				"details": err.Error(),
+			})
+			return
+		}
+		if respondPurchaseLimit(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to claim waitlist offer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Offer claimed, complete payment to receive your tickets",
+		"order":   order,
+	})
+}
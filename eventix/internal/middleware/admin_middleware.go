@@ -1,22 +0,0 @@
-package middleware
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-)
-
-func AdminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := GetUserRole(c)
-
-		if role != "admin" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "Admin access required",
-			})
-			return
-		}
-
-		c.Next()
-	}
-}
@@ -1,20 +1,31 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"eventix/internal/entity"
 	"eventix/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	UserIDKey = "userID"
-	RoleKey   = "role"
+	UserIDKey         = "userID"
+	RolesKey          = "roles"
+	PermissionsKey    = "permissions"
+	TokenIDKey        = "tokenID"
+	TokenExpiresAtKey = "tokenExpiresAt"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// TokenDenylist reports whether an access token was revoked before expiry.
+type TokenDenylist interface {
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
+
+func AuthMiddleware(denylist TokenDenylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -42,8 +53,30 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if claims.ID != "" {
+			revoked, err := denylist.IsAccessTokenRevoked(claims.ID)
+			if err != nil {
+				log.Printf("[Auth] Failed to check token denylist: %v", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to validate token",
+				})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "Token has been revoked",
+				})
+				return
+			}
+		}
+
 		c.Set(UserIDKey, claims.UserID)
-		c.Set(RoleKey, claims.Role)
+		c.Set(RolesKey, claims.Roles)
+		c.Set(PermissionsKey, claims.Permissions)
+		c.Set(TokenIDKey, claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set(TokenExpiresAtKey, claims.ExpiresAt.Time)
+		}
 
 		c.Next()
 	}
@@ -60,13 +93,33 @@ func GetUserID(c *gin.Context) uint {
 	return 0
 }
 
-func GetUserRole(c *gin.Context) string {
-	role, exists := c.Get(RoleKey)
-	if !exists {
-		return ""
-	}
-	if r, ok := role.(string); ok {
-		return r
+func GetUserRoles(c *gin.Context) []string {
+	return c.GetStringSlice(RolesKey)
+}
+
+func GetUserPermissions(c *gin.Context) []string {
+	return c.GetStringSlice(PermissionsKey)
+}
+
+// GetActor returns the authenticated user and their permissions for passing
+// into services that enforce ownership.
+func GetActor(c *gin.Context) entity.Actor {
+	return entity.Actor{
+		UserID:      GetUserID(c),
+		Permissions: GetUserPermissions(c),
 	}
-	return ""
+}
+
+// HasPermission reports whether the authenticated user's token grants permission.
+func HasPermission(c *gin.Context, permission string) bool {
+	return GetActor(c).Can(permission)
+}
+
+// GetTokenID returns the jti of the access token used for the request.
+func GetTokenID(c *gin.Context) string {
+	return c.GetString(TokenIDKey)
+}
+
+func GetTokenExpiresAt(c *gin.Context) time.Time {
+	return c.GetTime(TokenExpiresAtKey)
 }
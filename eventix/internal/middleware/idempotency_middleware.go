@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyClaimAttempts  = 2
+	idempotencyResponseFormat = "application/json; charset=utf-8"
+)
+
+// responseRecorder keeps a copy of everything the handler writes so it can be
+// stored and replayed for retries.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware honours the Idempotency-Key header on unsafe routes.
+// The first response for a (user, key) pair is stored and replayed verbatim on
+// retries; reusing a key with a different request is rejected with 422. Keys
+// older than ttl are forgotten, and a key still marked as processing after
+// lockTimeout is treated as abandoned by a crashed request and taken over.
+// Must run after AuthMiddleware and before rate limits, so replays are served
+// without using up a token.
+func IdempotencyMiddleware(repo repository.IdempotencyRepository, ttl, lockTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+			return
+		}
+
+		userID := GetUserID(c)
+		if userID == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := hashRequest(c.Request.Method, c.Request.URL.Path, body)
+
+		// Claim the key; if it is taken, decide between replay and rejection
+		record := &entity.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			Status:      entity.IdempotencyStatusProcessing,
+		}
+		claimed := false
+		for attempt := 0; attempt < idempotencyClaimAttempts; attempt++ {
+			err := repo.Create(record)
+			if err == nil {
+				claimed = true
+				break
+			}
+			if !errors.Is(err, repository.ErrNoRowsAffected) {
+				abortIdempotencyError(c, err)
+				return
+			}
+
+			existing, err := repo.Find(userID, key)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				abortIdempotencyError(c, err)
+				return
+			}
+
+			abandoned := existing.Status == entity.IdempotencyStatusProcessing && time.Since(existing.CreatedAt) > lockTimeout
+			if abandoned || time.Since(existing.CreatedAt) > ttl {
+				if err := repo.Delete(existing.ID); err != nil {
+					abortIdempotencyError(c, err)
+					return
+				}
+				continue
+			}
+
+			if existing.RequestHash != requestHash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "Idempotency-Key was already used with a different request",
+				})
+				return
+			}
+
+			if existing.Status != entity.IdempotencyStatusCompleted {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": "A request with this Idempotency-Key is still being processed",
+				})
+				return
+			}
+
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.StatusCode, idempotencyResponseFormat, []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		if !claimed {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error": "A request with this Idempotency-Key is still being processed",
+			})
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// A panicking handler must not leave the key stuck in PROCESSING
+		defer func() {
+			if r := recover(); r != nil {
+				if err := repo.Delete(record.ID); err != nil {
+					log.Printf("[Idempotency] Failed to release key %q for user %d: %v", key, userID, err)
+				}
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		// Server errors and rate limit rejections are not stored so the client
+		// can retry with the same key
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			if err := repo.Delete(record.ID); err != nil {
+				log.Printf("[Idempotency] Failed to release key %q for user %d: %v", key, userID, err)
+			}
+			return
+		}
+
+		if err := repo.Complete(record.ID, status, recorder.body.String()); err != nil {
+			log.Printf("[Idempotency] Failed to store response for key %q, user %d: %v", key, userID, err)
+		}
+	}
+}
+
+func hashRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func abortIdempotencyError(c *gin.Context, err error) {
+	log.Printf("[Idempotency] Storage error: %v", err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		"error": "Failed to process Idempotency-Key",
+	})
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission lets the request through only if the access token grants
+// every listed permission. Must run after AuthMiddleware.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if !HasPermission(c, permission) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error":    "Missing required permission",
+					"required": permissions,
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
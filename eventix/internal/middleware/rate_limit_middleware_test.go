@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eventix/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func newRateLimitedRouter(store ratelimit.Store, limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/limited", RateLimitMiddleware(store, "test", limit, KeyByIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func requestFrom(router *gin.Engine, addr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = addr
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Per: time.Hour})
+
+	for i, remaining := range []string{"1", "0"} {
+		rec := requestFrom(router, "192.0.2.1:1234")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get(RateLimitRemainingHeader); got != remaining {
+			t.Errorf("request %d: %s = %q, want %q", i+1, RateLimitRemainingHeader, got, remaining)
+		}
+		if got := rec.Header().Get(RetryAfterHeader); got != "" {
+			t.Errorf("request %d: unexpected %s %q", i+1, RetryAfterHeader, got)
+		}
+	}
+
+	rec := requestFrom(router, "192.0.2.1:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("drained bucket: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	wantHeaders := map[string]string{
+		RateLimitLimitHeader:     "2",
+		RateLimitRemainingHeader: "0",
+		RateLimitResetHeader:     "3600",
+		RateLimitPolicyHeader:    "2;w=3600",
+		RetryAfterHeader:         "1800",
+	}
+	for header, want := range wantHeaders {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	var body struct {
+		Error      string `json:"error"`
+		RetryAfter int    `json:"retry_after"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Error == "" || body.RetryAfter != 1800 {
+		t.Errorf("body = %+v, want an error and retry_after 1800", body)
+	}
+
+	if rec := requestFrom(router, "192.0.2.2:1234"); rec.Code != http.StatusOK {
+		t.Errorf("other client: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRateLimitMiddlewarePassThrough(t *testing.T) {
+	tests := map[string]*gin.Engine{
+		"disabled limit": newRateLimitedRouter(failingStore{}, ratelimit.Limit{}),
+		"failing store":  newRateLimitedRouter(failingStore{}, ratelimit.Limit{Requests: 1, Per: time.Hour}),
+	}
+	for name, router := range tests {
+		for i := 0; i < 3; i++ {
+			if rec := requestFrom(router, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+				t.Errorf("%s: request %d: status = %d, want %d", name, i+1, rec.Code, http.StatusOK)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrNoRowsAffected is returned by guarded updates whose WHERE clause matched
+// no rows, e.g. when the row is no longer in the expected state.
+var ErrNoRowsAffected = errors.New("no rows affected")
@@ -4,16 +4,25 @@ import (
 	"eventix/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository interface {
 	FindAll(filter entity.EventFilter) ([]entity.Event, int64, error)
 	FindByID(id uint) (*entity.Event, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Event, error)
 	Save(event *entity.Event) error
-	Update(event *entity.Event) error
+	Update(tx *gorm.DB, event *entity.Event) error
+	UpdatePurchaseLimit(id uint, limit *int) error
 	Delete(id uint) error
 	DecrementAvailableTickets(tx *gorm.DB, eventID uint, qty int) error
 	IncrementAvailableTickets(tx *gorm.DB, eventID uint, qty int) error
+	AdjustCapacity(tx *gorm.DB, eventID uint, delta int) error
+	SetCapacity(tx *gorm.DB, eventID uint, total, available int) error
+	FindCapacityDrift() ([]uint, error)
+	SyncPrice(tx *gorm.DB, eventID uint) error
+	SyncPrices() (int64, error)
+	GetDB() *gorm.DB
 }
 
 type eventRepository struct {
@@ -39,6 +48,10 @@ func (r *eventRepository) FindAll(filter entity.EventFilter) ([]entity.Event, in
 		query = query.Where("location ILIKE ?", "%"+filter.Location+"%")
 	}
 
+	if filter.OrganizerID != 0 {
+		query = query.Where("organizer_id = ?", filter.OrganizerID)
+	}
+
 	if !filter.DateFrom.IsZero() {
 		query = query.Where("date >= ?", filter.DateFrom)
 	}
@@ -68,7 +81,18 @@ func (r *eventRepository) FindAll(filter entity.EventFilter) ([]entity.Event, in
 
 func (r *eventRepository) FindByID(id uint) (*entity.Event, error) {
 	var event entity.Event
-	if err := r.db.First(&event, id).Error; err != nil {
+	if err := r.db.Preload("TicketTypes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("price ASC, id ASC")
+	}).First(&event, id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindByIDForUpdate loads an event while holding a row lock until tx ends.
+func (r *eventRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Event, error) {
+	var event entity.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, id).Error; err != nil {
 		return nil, err
 	}
 	return &event, nil
@@ -78,18 +102,44 @@ func (r *eventRepository) Save(event *entity.Event) error {
 	return r.db.Create(event).Error
 }
 
-func (r *eventRepository) Update(event *entity.Event) error {
-	return r.db.Save(event).Error
+func (r *eventRepository) Update(tx *gorm.DB, event *entity.Event) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Save(event).Error
+}
+
+// UpdatePurchaseLimit sets only the per-user ticket limit, so it cannot undo
+// a concurrent update of the event's other fields.
+func (r *eventRepository) UpdatePurchaseLimit(id uint, limit *int) error {
+	result := r.db.Model(&entity.Event{}).Where("id = ?", id).Update("max_tickets_per_user", limit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
 }
 
 func (r *eventRepository) Delete(id uint) error {
 	return r.db.Delete(&entity.Event{}, id).Error
 }
 
+// DecrementAvailableTickets reserves qty tickets with a conditional update, so
+// concurrent bookings on any number of instances can never oversell. It returns
+// ErrNoRowsAffected when fewer than qty tickets are left.
 func (r *eventRepository) DecrementAvailableTickets(tx *gorm.DB, eventID uint, qty int) error {
-	return tx.Model(&entity.Event{}).
+	result := tx.Model(&entity.Event{}).
 		Where("id = ? AND available_tickets >= ?", eventID, qty).
-		UpdateColumn("available_tickets", gorm.Expr("available_tickets - ?", qty)).Error
+		UpdateColumn("available_tickets", gorm.Expr("available_tickets - ?", qty))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
 }
 
 func (r *eventRepository) IncrementAvailableTickets(tx *gorm.DB, eventID uint, qty int) error {
@@ -97,3 +147,76 @@ func (r *eventRepository) IncrementAvailableTickets(tx *gorm.DB, eventID uint, q
 		Where("id = ?", eventID).
 		UpdateColumn("available_tickets", gorm.Expr("available_tickets + ?", qty)).Error
 }
+
+// AdjustCapacity grows or shrinks the event's ticket pool by delta, following
+// a change to the size of one of its tiers.
+func (r *eventRepository) AdjustCapacity(tx *gorm.DB, eventID uint, delta int) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumns(map[string]interface{}{
+			"total_tickets":     gorm.Expr("total_tickets + ?", delta),
+			"available_tickets": gorm.Expr("available_tickets + ?", delta),
+		}).Error
+}
+
+// SetCapacity overwrites the event's ticket pool. Callers must hold the locks
+// of the event's tiers and of the event itself.
+func (r *eventRepository) SetCapacity(tx *gorm.DB, eventID uint, total, available int) error {
+	return tx.Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumns(map[string]interface{}{
+			"total_tickets":     total,
+			"available_tickets": available,
+		}).Error
+}
+
+// FindCapacityDrift lists the events whose ticket pool differs from the sums
+// over their tiers, which older tier edits did not keep in step. It takes no
+// locks, so callers must check again under lock before correcting an event.
+func (r *eventRepository) FindCapacityDrift() ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		SELECT events.id
+		FROM events
+		JOIN (
+			SELECT event_id, SUM(total_quantity) AS total, SUM(available_quantity) AS available
+			FROM ticket_types
+			GROUP BY event_id
+		) t ON t.event_id = events.id
+		WHERE events.total_tickets <> t.total OR events.available_tickets <> t.available
+		ORDER BY events.id`).Scan(&ids).Error
+	return ids, err
+}
+
+// SyncPrice sets the event's listed price to its cheapest tier, following a
+// change to the price or the set of its tiers.
+func (r *eventRepository) SyncPrice(tx *gorm.DB, eventID uint) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumn("price", gorm.Expr("COALESCE((SELECT MIN(price) FROM ticket_types WHERE event_id = ?), price)", eventID)).Error
+}
+
+// SyncPrices sets the listed price of every event whose price differs from
+// its cheapest tier, which older tier edits did not keep in step.
+func (r *eventRepository) SyncPrices() (int64, error) {
+	result := r.db.Exec(`
+		UPDATE events
+		SET price = t.price
+		FROM (
+			SELECT event_id, MIN(price) AS price
+			FROM ticket_types
+			GROUP BY event_id
+		) t
+		WHERE t.event_id = events.id AND events.price <> t.price`)
+	return result.RowsAffected, result.Error
+}
+
+func (r *eventRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type EventSeatRepository interface {
+	SaveBatch(tx *gorm.DB, seats []entity.EventSeat) error
+	CountByEventID(tx *gorm.DB, eventID uint) (int64, error)
+	FindAvailability(eventID uint) ([]entity.SeatAvailability, error)
+	FindBySeatIDs(eventID uint, seatIDs []uint) ([]entity.EventSeat, error)
+	FindByOrderID(tx *gorm.DB, orderID uint) ([]entity.EventSeat, error)
+	Hold(tx *gorm.DB, eventID uint, seatIDs []uint, orderID uint) error
+	Reserve(tx *gorm.DB, eventID uint, seatIDs []uint, holdID uint) error
+	AssignHoldToOrder(tx *gorm.DB, holdID, orderID uint) error
+	ReleaseByHoldID(tx *gorm.DB, holdID uint) error
+	MarkSold(tx *gorm.DB, orderID uint) error
+	ReleaseByOrderID(tx *gorm.DB, orderID uint) error
+	Release(tx *gorm.DB, eventID uint, seatIDs []uint) error
+}
+
+type eventSeatRepository struct {
+	db *gorm.DB
+}
+
+func NewEventSeatRepository(db *gorm.DB) EventSeatRepository {
+	return &eventSeatRepository{db: db}
+}
+
+func (r *eventSeatRepository) SaveBatch(tx *gorm.DB, seats []entity.EventSeat) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Omit("Event", "TicketType", "Seat").CreateInBatches(&seats, 500).Error
+}
+
+func (r *eventSeatRepository) CountByEventID(tx *gorm.DB, eventID uint) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var count int64
+	err := tx.Model(&entity.EventSeat{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
+
+// FindAvailability lists every seat on sale for the event with its position,
+// price and current status, ordered as the seat map is laid out.
+func (r *eventSeatRepository) FindAvailability(eventID uint) ([]entity.SeatAvailability, error) {
+	var seats []entity.SeatAvailability
+	err := r.db.Model(&entity.EventSeat{}).
+		Select(`event_seats.seat_id, venue_sections.name AS section, seats.row, seats.number, seats.label,
+			seats.accessible, seats.companion, event_seats.ticket_type_id, ticket_types.price, event_seats.status`).
+		Joins("JOIN seats ON seats.id = event_seats.seat_id").
+		Joins("JOIN venue_sections ON venue_sections.id = seats.section_id").
+		Joins("JOIN ticket_types ON ticket_types.id = event_seats.ticket_type_id").
+		Where("event_seats.event_id = ?", eventID).
+		Order("seats.id ASC").
+		Scan(&seats).Error
+	return seats, err
+}
+
+func (r *eventSeatRepository) FindBySeatIDs(eventID uint, seatIDs []uint) ([]entity.EventSeat, error) {
+	var seats []entity.EventSeat
+	err := r.db.Where("event_id = ? AND seat_id IN ?", eventID, seatIDs).
+		Order("seat_id ASC").
+		Find(&seats).Error
+	return seats, err
+}
+
+// FindByOrderID returns the seats held or bought by an order with their
+// venue seat loaded.
+func (r *eventSeatRepository) FindByOrderID(tx *gorm.DB, orderID uint) ([]entity.EventSeat, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var seats []entity.EventSeat
+	err := tx.Preload("Seat").Where("order_id = ?", orderID).Order("seat_id ASC").Find(&seats).Error
+	return seats, err
+}
+
+// Hold assigns available seats to a pending order. It returns
+// ErrNoRowsAffected unless every seat was still available, so two orders can
+// never hold the same seat.
+func (r *eventSeatRepository) Hold(tx *gorm.DB, eventID uint, seatIDs []uint, orderID uint) error {
+	result := tx.Model(&entity.EventSeat{}).
+		Where("event_id = ? AND seat_id IN ? AND status = ?", eventID, seatIDs, entity.SeatStatusAvailable).
+		Updates(map[string]interface{}{
+			"status":   entity.SeatStatusHeld,
+			"order_id": orderID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(seatIDs)) {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// Reserve assigns available seats to a cart hold, guarded like Hold.
+func (r *eventSeatRepository) Reserve(tx *gorm.DB, eventID uint, seatIDs []uint, holdID uint) error {
+	result := tx.Model(&entity.EventSeat{}).
+		Where("event_id = ? AND seat_id IN ? AND status = ?", eventID, seatIDs, entity.SeatStatusAvailable).
+		Updates(map[string]interface{}{
+			"status":  entity.SeatStatusHeld,
+			"hold_id": holdID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(seatIDs)) {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// AssignHoldToOrder hands the seats of a checked out hold to its order; they
+// stay HELD until the order is paid.
+func (r *eventSeatRepository) AssignHoldToOrder(tx *gorm.DB, holdID, orderID uint) error {
+	return tx.Model(&entity.EventSeat{}).
+		Where("hold_id = ? AND status = ?", holdID, entity.SeatStatusHeld).
+		Updates(map[string]interface{}{
+			"hold_id":  nil,
+			"order_id": orderID,
+		}).Error
+}
+
+// ReleaseByHoldID frees the seats of a hold that was released or ran out.
+func (r *eventSeatRepository) ReleaseByHoldID(tx *gorm.DB, holdID uint) error {
+	return tx.Model(&entity.EventSeat{}).
+		Where("hold_id = ? AND status = ?", holdID, entity.SeatStatusHeld).
+		Updates(map[string]interface{}{
+			"status":  entity.SeatStatusAvailable,
+			"hold_id": nil,
+		}).Error
+}
+
+func (r *eventSeatRepository) MarkSold(tx *gorm.DB, orderID uint) error {
+	return tx.Model(&entity.EventSeat{}).
+		Where("order_id = ? AND status = ?", orderID, entity.SeatStatusHeld).
+		Update("status", entity.SeatStatusSold).Error
+}
+
+// ReleaseByOrderID frees the seats an unpaid order was holding.
+func (r *eventSeatRepository) ReleaseByOrderID(tx *gorm.DB, orderID uint) error {
+	return tx.Model(&entity.EventSeat{}).
+		Where("order_id = ? AND status = ?", orderID, entity.SeatStatusHeld).
+		Updates(map[string]interface{}{
+			"status":   entity.SeatStatusAvailable,
+			"order_id": nil,
+		}).Error
+}
+
+// Release puts sold seats back on sale, e.g. after their tickets were refunded.
+func (r *eventSeatRepository) Release(tx *gorm.DB, eventID uint, seatIDs []uint) error {
+	return tx.Model(&entity.EventSeat{}).
+		Where("event_id = ? AND seat_id IN ?", eventID, seatIDs).
+		Updates(map[string]interface{}{
+			"status":   entity.SeatStatusAvailable,
+			"order_id": nil,
+		}).Error
+}
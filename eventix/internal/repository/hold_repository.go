@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HoldRepository interface {
+	Save(tx *gorm.DB, hold *entity.Hold) error
+	FindByID(id uint) (*entity.Hold, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Hold, error)
+	FindActiveByUserID(userID uint) ([]entity.Hold, error)
+	FindExpired(now time.Time, limit int) ([]entity.Hold, error)
+	CountActiveTicketsByUser(tx *gorm.DB, userID, eventID uint, now time.Time) (int64, error)
+	Extend(tx *gorm.DB, id uint, expiresAt, extendedAt time.Time) error
+	Expire(tx *gorm.DB, id uint, now time.Time) error
+	MarkConverted(tx *gorm.DB, id uint, orderID uint) error
+	TransitionStatus(tx *gorm.DB, id uint, from, to entity.HoldStatus) error
+	GetDB() *gorm.DB
+}
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+func (r *holdRepository) Save(tx *gorm.DB, hold *entity.Hold) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Omit("Event", "Seats").Create(hold).Error
+}
+
+func (r *holdRepository) FindByID(id uint) (*entity.Hold, error) {
+	var hold entity.Hold
+	if err := r.db.Preload("Event").Preload("Items").
+		Preload("Seats", func(db *gorm.DB) *gorm.DB { return db.Order("seat_id ASC") }).
+		First(&hold, id).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// FindByIDForUpdate loads a hold with its items while holding a row lock
+// until tx ends.
+func (r *holdRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Hold, error) {
+	var hold entity.Hold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&hold, id).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *holdRepository) FindActiveByUserID(userID uint) ([]entity.Hold, error) {
+	var holds []entity.Hold
+	if err := r.db.Preload("Event").Preload("Items").
+		Preload("Seats", func(db *gorm.DB) *gorm.DB { return db.Order("seat_id ASC") }).
+		Where("user_id = ? AND status = ?", userID, entity.HoldStatusActive).
+		Order("expires_at ASC").
+		Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+func (r *holdRepository) FindExpired(now time.Time, limit int) ([]entity.Hold, error) {
+	var holds []entity.Hold
+	if err := r.db.Preload("Items").
+		Where("status = ? AND expires_at <= ?", entity.HoldStatusActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// CountActiveTicketsByUser counts the tickets in a user's unexpired holds for
+// an event.
+func (r *holdRepository) CountActiveTicketsByUser(tx *gorm.DB, userID, eventID uint, now time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var count int64
+	err := tx.Model(&entity.Hold{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("user_id = ? AND event_id = ? AND status = ? AND expires_at > ?", userID, eventID, entity.HoldStatusActive, now).
+		Scan(&count).Error
+	return count, err
+}
+
+// Extend moves the expiry of an active hold that has not been extended yet,
+// returning ErrNoRowsAffected otherwise.
+func (r *holdRepository) Extend(tx *gorm.DB, id uint, expiresAt, extendedAt time.Time) error {
+	result := tx.Model(&entity.Hold{}).
+		Where("id = ? AND status = ? AND extended_at IS NULL", id, entity.HoldStatusActive).
+		Updates(map[string]interface{}{
+			"expires_at":  expiresAt,
+			"extended_at": extendedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// Expire marks a hold EXPIRED if it is still active and past its expiry at
+// now. A hold checked out, released or extended in the meantime is left alone
+// and ErrNoRowsAffected returned, so reapers on several instances can race
+// safely.
+func (r *holdRepository) Expire(tx *gorm.DB, id uint, now time.Time) error {
+	result := tx.Model(&entity.Hold{}).
+		Where("id = ? AND status = ? AND expires_at <= ?", id, entity.HoldStatusActive, now).
+		Update("status", entity.HoldStatusExpired)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *holdRepository) MarkConverted(tx *gorm.DB, id uint, orderID uint) error {
+	result := tx.Model(&entity.Hold{}).
+		Where("id = ? AND status = ?", id, entity.HoldStatusActive).
+		Updates(map[string]interface{}{
+			"status":   entity.HoldStatusConverted,
+			"order_id": orderID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// TransitionStatus moves a hold to a new status only if it is still in the
+// expected one, returning ErrNoRowsAffected otherwise.
+func (r *holdRepository) TransitionStatus(tx *gorm.DB, id uint, from, to entity.HoldStatus) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Model(&entity.Hold{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *holdRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Create(record *entity.IdempotencyKey) error
+	Find(userID uint, key string) (*entity.IdempotencyKey, error)
+	Complete(id uint, statusCode int, body string) error
+	Delete(id uint) error
+	PurgeExpired(before time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Create claims a key for a user. It returns ErrNoRowsAffected if the key is
+// already taken, which lets concurrent retries race safely on the unique index.
+func (r *idempotencyRepository) Create(record *entity.IdempotencyKey) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Find(userID uint, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	if err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Complete(id uint, statusCode int, body string) error {
+	return r.db.Model(&entity.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        entity.IdempotencyStatusCompleted,
+		"status_code":   statusCode,
+		"response_body": body,
+	}).Error
+}
+
+func (r *idempotencyRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.IdempotencyKey{}, id).Error
+}
+
+// PurgeExpired deletes keys created before the given time, which can no
+// longer be replayed, together with their stored responses.
+func (r *idempotencyRepository) PurgeExpired(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -1,16 +1,26 @@
 package repository
 
 import (
+	"time"
+
 	"eventix/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
 	Save(tx *gorm.DB, order *entity.Order) error
 	FindByID(id uint) (*entity.Order, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Order, error)
 	FindByUserID(userID uint) ([]entity.Order, error)
+	FindAll(filter entity.OrderFilter) ([]entity.Order, int64, error)
 	UpdateStatus(tx *gorm.DB, orderID uint, status entity.OrderStatus) error
+	TransitionStatus(tx *gorm.DB, orderID uint, from, to entity.OrderStatus) error
+	FindExpiredPending(now time.Time, limit int) ([]entity.Order, error)
+	BackfillExpiry(window time.Duration) (int64, error)
+	ApplyRefund(tx *gorm.DB, orderID uint, from, to entity.OrderStatus, amount float64) error
+	CountTicketsByUser(tx *gorm.DB, userID, eventID uint) (int64, error)
 	GetDB() *gorm.DB
 }
 
@@ -31,7 +41,16 @@ func (r *orderRepository) Save(tx *gorm.DB, order *entity.Order) error {
 
 func (r *orderRepository) FindByID(id uint) (*entity.Order, error) {
 	var order entity.Order
-	if err := r.db.Preload("Event").Preload("Tickets").First(&order, id).Error; err != nil {
+	if err := r.db.Preload("Event").Preload("Items").Preload("Tickets.Seat").Preload("Payments").Preload("Refunds").First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByIDForUpdate loads an order while holding a row lock until tx ends.
+func (r *orderRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Order, error) {
+	var order entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&order, id).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
@@ -39,12 +58,43 @@ func (r *orderRepository) FindByID(id uint) (*entity.Order, error) {
 
 func (r *orderRepository) FindByUserID(userID uint) ([]entity.Order, error) {
 	var orders []entity.Order
-	if err := r.db.Preload("Event").Preload("Tickets").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders).Error; err != nil {
+	if err := r.db.Preload("Event").Preload("Items").Preload("Tickets.Seat").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
+// FindAll lists orders from every user, newest first. Zero-valued filter
+// fields match all orders.
+func (r *orderRepository) FindAll(filter entity.OrderFilter) ([]entity.Order, int64, error) {
+	var orders []entity.Order
+	var total int64
+
+	query := r.db.Model(&entity.Order{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EventID != 0 {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	if err := query.Preload("Event").Order("created_at DESC").Limit(filter.PageSize).Offset(offset).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
 func (r *orderRepository) UpdateStatus(tx *gorm.DB, orderID uint, status entity.OrderStatus) error {
 	if tx == nil {
 		tx = r.db
@@ -52,6 +102,92 @@ func (r *orderRepository) UpdateStatus(tx *gorm.DB, orderID uint, status entity.
 	return tx.Model(&entity.Order{}).Where("id = ?", orderID).Update("status", status).Error
 }
 
+// TransitionStatus moves an order to a new status only if it is still in the
+// expected one, returning ErrNoRowsAffected when another request got there first.
+func (r *orderRepository) TransitionStatus(tx *gorm.DB, orderID uint, from, to entity.OrderStatus) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Model(&entity.Order{}).
+		Where("id = ? AND status = ?", orderID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// ApplyRefund adds amount to the order's refunded total and moves it to the
+// given status, guarded like TransitionStatus.
+func (r *orderRepository) ApplyRefund(tx *gorm.DB, orderID uint, from, to entity.OrderStatus, amount float64) error {
+	result := tx.Model(&entity.Order{}).
+		Where("id = ? AND status = ?", orderID, from).
+		Updates(map[string]interface{}{
+			"status":          to,
+			"refunded_amount": gorm.Expr("refunded_amount + ?", amount),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// BackfillExpiry gives PENDING orders created before payment windows existed
+// an expiry of created_at + window, so the reaper can release them too. It
+// returns how many orders were updated.
+func (r *orderRepository) BackfillExpiry(window time.Duration) (int64, error) {
+	result := r.db.Exec(`
+		UPDATE orders SET expires_at = created_at + ? * INTERVAL '1 second'
+		WHERE status = ? AND expires_at IS NULL`, window.Seconds(), entity.OrderStatusPending)
+	return result.RowsAffected, result.Error
+}
+
+func (r *orderRepository) FindExpiredPending(now time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Preload("Items").
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", entity.OrderStatusPending, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CountTicketsByUser counts the tickets a user has bought or is about to buy
+// for an event: those of PENDING and PAID orders, and the ones not refunded
+// of partially refunded orders.
+func (r *orderRepository) CountTicketsByUser(tx *gorm.DB, userID, eventID uint) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var ordered int64
+	if err := tx.Model(&entity.Order{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID, []entity.OrderStatus{
+			entity.OrderStatusPending, entity.OrderStatusPaid, entity.OrderStatusPartiallyRefunded,
+		}).
+		Scan(&ordered).Error; err != nil {
+		return 0, err
+	}
+
+	var refunded int64
+	if err := tx.Model(&entity.Ticket{}).
+		Joins("JOIN orders ON orders.id = tickets.order_id").
+		Where("orders.user_id = ? AND orders.event_id = ? AND orders.status = ? AND tickets.status = ?",
+			userID, eventID, entity.OrderStatusPartiallyRefunded, entity.TicketStatusRefunded).
+		Count(&refunded).Error; err != nil {
+		return 0, err
+	}
+	return ordered - refunded, nil
+}
+
 func (r *orderRepository) GetDB() *gorm.DB {
 	return r.db
 }
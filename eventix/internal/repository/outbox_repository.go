@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	Enqueue(tx *gorm.DB, topic string, payload interface{}) error
+	Claim(limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	Renew(id uint, token string, lease time.Duration) error
+	MarkDone(id uint, token string) error
+	MarkFailed(id uint, token, reason string, retryAt time.Time) error
+	MarkDead(id uint, token, reason string) error
+	Release(ids []uint, token string) error
+	FindDead(limit, offset int) ([]entity.OutboxMessage, int64, error)
+	Requeue(id uint) error
+	CountByStatus() (map[entity.OutboxStatus]int64, error)
+	PurgeDone(before time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Enqueue stores payload as JSON under topic. Pass the surrounding business
+// transaction so the message commits or rolls back together with it.
+func (r *outboxRepository) Enqueue(tx *gorm.DB, topic string, payload interface{}) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.OutboxMessage{
+		Topic:       topic,
+		Payload:     string(data),
+		Status:      entity.OutboxStatusPending,
+		AvailableAt: time.Now(),
+	}).Error
+}
+
+// Claim leases up to limit due messages to the caller under a fresh claim
+// token. Rows are selected with FOR UPDATE SKIP LOCKED so several dispatchers
+// never claim the same message, and a message whose lease ran out (e.g. the
+// process crashed mid-send) becomes claimable again.
+func (r *outboxRepository) Claim(limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	now := time.Now()
+
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND available_at <= ?) OR (status = ? AND locked_until < ?)",
+				entity.OutboxStatusPending, now, entity.OutboxStatusProcessing, now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+		}
+
+		lockedUntil := now.Add(lease)
+		if err := tx.Model(&entity.OutboxMessage{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusProcessing,
+			"locked_until": lockedUntil,
+			"claim_token":  token,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		for i := range messages {
+			messages[i].Status = entity.OutboxStatusProcessing
+			messages[i].LockedUntil = &lockedUntil
+			messages[i].ClaimToken = token
+			messages[i].Attempts++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Renew restarts the lease of a claimed message just before it is processed,
+// so messages late in a batch get a full lease too. It returns
+// ErrNoRowsAffected when the claim was lost to another dispatcher.
+func (r *outboxRepository) Renew(id uint, token string, lease time.Duration) error {
+	return r.updateClaimed(id, token, map[string]interface{}{
+		"locked_until": time.Now().Add(lease),
+	})
+}
+
+func (r *outboxRepository) MarkDone(id uint, token string) error {
+	return r.updateClaimed(id, token, map[string]interface{}{
+		"status":       entity.OutboxStatusDone,
+		"locked_until": nil,
+		"processed_at": time.Now(),
+	})
+}
+
+// MarkFailed releases the lease and schedules the message for another attempt.
+func (r *outboxRepository) MarkFailed(id uint, token, reason string, retryAt time.Time) error {
+	return r.updateClaimed(id, token, map[string]interface{}{
+		"status":       entity.OutboxStatusPending,
+		"locked_until": nil,
+		"available_at": retryAt,
+		"last_error":   reason,
+	})
+}
+
+// Release returns claimed but unprocessed messages to the queue without
+// counting the claim as an attempt.
+func (r *outboxRepository) Release(ids []uint, token string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.OutboxMessage{}).
+		Where("id IN ? AND status = ? AND claim_token = ?", ids, entity.OutboxStatusProcessing, token).
+		Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusPending,
+			"locked_until": nil,
+			"attempts":     gorm.Expr("GREATEST(attempts - 1, 0)"),
+		}).Error
+}
+
+// MarkDead parks a message that exhausted its attempts in the dead-letter state.
+func (r *outboxRepository) MarkDead(id uint, token, reason string) error {
+	return r.updateClaimed(id, token, map[string]interface{}{
+		"status":       entity.OutboxStatusDead,
+		"locked_until": nil,
+		"last_error":   reason,
+	})
+}
+
+// updateClaimed applies updates only while the message is still processing
+// under the given claim. It returns ErrNoRowsAffected once the lease ran out
+// and another dispatcher reclaimed the message.
+func (r *outboxRepository) updateClaimed(id uint, token string, updates map[string]interface{}) error {
+	result := r.db.Model(&entity.OutboxMessage{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, entity.OutboxStatusProcessing, token).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func newClaimToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (r *outboxRepository) FindDead(limit, offset int) ([]entity.OutboxMessage, int64, error) {
+	var messages []entity.OutboxMessage
+	var total int64
+
+	query := r.db.Model(&entity.OutboxMessage{}).Where("status = ?", entity.OutboxStatusDead)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// Requeue gives a dead message a fresh set of attempts. It returns
+// ErrNoRowsAffected if the message does not exist or is not dead.
+func (r *outboxRepository) Requeue(id uint) error {
+	result := r.db.Model(&entity.OutboxMessage{}).
+		Where("id = ? AND status = ?", id, entity.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusPending,
+			"attempts":     0,
+			"available_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *outboxRepository) CountByStatus() (map[entity.OutboxStatus]int64, error) {
+	var rows []struct {
+		Status entity.OutboxStatus
+		Count  int64
+	}
+	if err := r.db.Model(&entity.OutboxMessage{}).
+		Select("status, COUNT(*) AS count").
+		Where("status <> ?", entity.OutboxStatusDone).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.OutboxStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// PurgeDone deletes messages that were processed before the given time. Dead
+// letters are kept until an operator requeues them.
+func (r *outboxRepository) PurgeDone(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND processed_at < ?", entity.OutboxStatusDone, before).Delete(&entity.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository interface {
+	Save(tx *gorm.DB, payment *entity.Payment) error
+	Update(tx *gorm.DB, payment *entity.Payment) error
+	FindByID(id uint) (*entity.Payment, error)
+	FindByOrderID(orderID uint) ([]entity.Payment, error)
+	FindByProviderRef(tx *gorm.DB, provider, reference string) (*entity.Payment, error)
+	SaveWebhookEvent(tx *gorm.DB, event *entity.PaymentWebhookEvent) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Save(tx *gorm.DB, payment *entity.Payment) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(payment).Error
+}
+
+func (r *paymentRepository) Update(tx *gorm.DB, payment *entity.Payment) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Save(payment).Error
+}
+
+func (r *paymentRepository) FindByID(id uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) FindByOrderID(orderID uint) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) FindByProviderRef(tx *gorm.DB, provider, reference string) (*entity.Payment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var payment entity.Payment
+	if err := tx.Where("provider = ? AND provider_ref = ?", provider, reference).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// SaveWebhookEvent records a delivery and returns ErrNoRowsAffected if the same
+// provider event was already stored, which makes replays a no-op.
+func (r *paymentRepository) SaveWebhookEvent(tx *gorm.DB, event *entity.PaymentWebhookEvent) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromoCodeRepository interface {
+	Save(promo *entity.PromoCode) error
+	Update(promo *entity.PromoCode) error
+	Delete(id uint) error
+	FindByID(id uint) (*entity.PromoCode, error)
+	FindByCode(code string) (*entity.PromoCode, error)
+	FindByCodeForUpdate(tx *gorm.DB, code string) (*entity.PromoCode, error)
+	FindAll(filter entity.PromoCodeFilter) ([]entity.PromoCode, int64, error)
+	CountRedemptions(promoCodeID uint) (int64, error)
+	CountActiveRedemptionsByUser(tx *gorm.DB, promoCodeID uint, userID uint) (int64, error)
+	Redeem(tx *gorm.DB, redemption *entity.PromoRedemption) error
+	ReleaseByOrderID(tx *gorm.DB, orderID uint, releasedAt time.Time) error
+}
+
+type promoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPromoCodeRepository(db *gorm.DB) PromoCodeRepository {
+	return &promoCodeRepository{db: db}
+}
+
+func (r *promoCodeRepository) Save(promo *entity.PromoCode) error {
+	return r.db.Create(promo).Error
+}
+
+func (r *promoCodeRepository) Update(promo *entity.PromoCode) error {
+	return r.db.Save(promo).Error
+}
+
+func (r *promoCodeRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.PromoCode{}, id).Error
+}
+
+func (r *promoCodeRepository) FindByID(id uint) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+	if err := r.db.First(&promo, id).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *promoCodeRepository) FindByCode(code string) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+	if err := r.db.Where("code = ?", strings.ToUpper(code)).First(&promo).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+// FindByCodeForUpdate loads a code while holding a row lock until tx ends, so
+// concurrent bookings check and consume its limits one at a time.
+func (r *promoCodeRepository) FindByCodeForUpdate(tx *gorm.DB, code string) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", strings.ToUpper(code)).
+		First(&promo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *promoCodeRepository) FindAll(filter entity.PromoCodeFilter) ([]entity.PromoCode, int64, error) {
+	var promos []entity.PromoCode
+	var total int64
+
+	query := r.db.Model(&entity.PromoCode{})
+	if filter.EventID != 0 {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	if err := query.Order("created_at DESC").Limit(filter.PageSize).Offset(offset).Find(&promos).Error; err != nil {
+		return nil, 0, err
+	}
+	return promos, total, nil
+}
+
+// CountRedemptions counts every redemption of a code, including released ones.
+func (r *promoCodeRepository) CountRedemptions(promoCodeID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.PromoRedemption{}).
+		Where("promo_code_id = ?", promoCodeID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *promoCodeRepository) CountActiveRedemptionsByUser(tx *gorm.DB, promoCodeID uint, userID uint) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var count int64
+	err := tx.Model(&entity.PromoRedemption{}).
+		Where("promo_code_id = ? AND user_id = ? AND status = ?", promoCodeID, userID, entity.RedemptionStatusActive).
+		Count(&count).Error
+	return count, err
+}
+
+// Redeem records the redemption and bumps the code's counter. The counter is
+// guarded by max_redemptions; ErrNoRowsAffected means the code is used up.
+func (r *promoCodeRepository) Redeem(tx *gorm.DB, redemption *entity.PromoRedemption) error {
+	result := tx.Model(&entity.PromoCode{}).
+		Where("id = ? AND (max_redemptions = 0 OR redemption_count < max_redemptions)", redemption.PromoCodeID).
+		UpdateColumn("redemption_count", gorm.Expr("redemption_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return tx.Create(redemption).Error
+}
+
+// ReleaseByOrderID gives back the order's redemption, if it has an active one.
+func (r *promoCodeRepository) ReleaseByOrderID(tx *gorm.DB, orderID uint, releasedAt time.Time) error {
+	var redemption entity.PromoRedemption
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", orderID, entity.RedemptionStatusActive).
+		First(&redemption).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Model(&redemption).Updates(map[string]interface{}{
+		"status":      entity.RedemptionStatusReleased,
+		"released_at": releasedAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entity.PromoCode{}).
+		Where("id = ? AND redemption_count > 0", redemption.PromoCodeID).
+		UpdateColumn("redemption_count", gorm.Expr("redemption_count - 1")).Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type RefundRepository interface {
+	Save(tx *gorm.DB, refund *entity.Refund) error
+	FindByID(id uint) (*entity.Refund, error)
+	FindByOrderID(orderID uint) ([]entity.Refund, error)
+	MarkProcessing(id uint) error
+	MarkSucceeded(id uint) error
+	RecordFailure(id uint, reason string) error
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) Save(tx *gorm.DB, refund *entity.Refund) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(refund).Error
+}
+
+func (r *refundRepository) FindByID(id uint) (*entity.Refund, error) {
+	var refund entity.Refund
+	if err := r.db.First(&refund, id).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *refundRepository) FindByOrderID(orderID uint) ([]entity.Refund, error) {
+	var refunds []entity.Refund
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// MarkProcessing claims a pending refund for payout before the provider is
+// called. It returns ErrNoRowsAffected when the refund is no longer pending.
+func (r *refundRepository) MarkProcessing(id uint) error {
+	result := r.db.Model(&entity.Refund{}).
+		Where("id = ? AND status = ?", id, entity.RefundStatusPending).
+		Update("status", entity.RefundStatusProcessing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// MarkSucceeded completes a refund being paid out. It returns
+// ErrNoRowsAffected when the refund is not processing, e.g. because a
+// duplicate delivery of its payout already completed it.
+func (r *refundRepository) MarkSucceeded(id uint) error {
+	result := r.db.Model(&entity.Refund{}).
+		Where("id = ? AND status = ?", id, entity.RefundStatusProcessing).
+		Updates(map[string]interface{}{
+			"status":         entity.RefundStatusSucceeded,
+			"failure_reason": "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// RecordFailure keeps the reason a payout failed on a refund that stays
+// processing until a retry succeeds.
+func (r *refundRepository) RecordFailure(id uint, reason string) error {
+	return r.db.Model(&entity.Refund{}).
+		Where("id = ? AND status = ?", id, entity.RefundStatusProcessing).
+		Update("failure_reason", reason).Error
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository interface {
+	EnsurePermissions(permissions []entity.Permission) error
+	EnsureRole(role *entity.Role) (bool, error)
+	AddPermissions(roleID uint, names []string) error
+	RemovePermission(roleID, permissionID uint) error
+	FindPermissionByName(name string) (*entity.Permission, error)
+	FindAll() ([]entity.Role, error)
+	FindAllPermissions() ([]entity.Permission, error)
+	FindByName(name string) (*entity.Role, error)
+	FindByUserID(userID uint) ([]entity.Role, error)
+	AssignToUser(tx *gorm.DB, userID, roleID uint) error
+	RemoveFromUser(userID, roleID uint) error
+	MigrateLegacyUserRoles(defaultRole string) (int64, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// EnsurePermissions inserts permissions that do not exist yet.
+func (r *roleRepository) EnsurePermissions(permissions []entity.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	// Insert a copy so generated IDs are not written back into the caller's slice
+	rows := append([]entity.Permission(nil), permissions...)
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&rows).Error
+}
+
+// EnsureRole creates the role if no role with its name exists and loads the
+// stored row into role either way. It reports whether the role was created.
+func (r *roleRepository) EnsureRole(role *entity.Role) (bool, error) {
+	result := r.db.Where(entity.Role{Name: role.Name}).
+		Attrs(entity.Role{Description: role.Description}).
+		FirstOrCreate(role)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// AddPermissions grants the named permissions to a role; ones it already has
+// are skipped.
+func (r *roleRepository) AddPermissions(roleID uint, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return r.db.Exec(`
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT ?, id FROM permissions WHERE name IN ?
+		ON CONFLICT DO NOTHING`, roleID, names).Error
+}
+
+// RemovePermission returns ErrNoRowsAffected if the role does not hold the
+// permission.
+func (r *roleRepository) RemovePermission(roleID, permissionID uint) error {
+	result := r.db.Exec("DELETE FROM role_permissions WHERE role_id = ? AND permission_id = ?", roleID, permissionID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *roleRepository) FindPermissionByName(name string) (*entity.Permission, error) {
+	var permission entity.Permission
+	if err := r.db.Where("name = ?", name).First(&permission).Error; err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *roleRepository) FindAll() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindAllPermissions() ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := r.db.Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) FindByName(name string) (*entity.Role, error) {
+	var role entity.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByUserID(userID uint) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+// AssignToUser returns ErrNoRowsAffected if the user already has the role.
+func (r *roleRepository) AssignToUser(tx *gorm.DB, userID, roleID uint) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Exec(
+		"INSERT INTO user_roles (user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		userID, roleID,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// RemoveFromUser returns ErrNoRowsAffected if the user does not have the role.
+func (r *roleRepository) RemoveFromUser(userID, roleID uint) error {
+	result := r.db.Exec("DELETE FROM user_roles WHERE user_id = ? AND role_id = ?", userID, roleID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// MigrateLegacyUserRoles converts the old free-text users.role column into
+// role assignments for users that have none yet, e.g. accounts created by an
+// older instance during a rolling deploy. Unknown values fall back to
+// defaultRole. The column is kept so a rollback still finds it; it is a no-op
+// once the column is gone.
+func (r *roleRepository) MigrateLegacyUserRoles(defaultRole string) (int64, error) {
+	if !r.db.Migrator().HasColumn("users", "role") {
+		return 0, nil
+	}
+
+	result := r.db.Exec(`
+		INSERT INTO user_roles (user_id, role_id)
+		SELECT u.id, COALESCE(r.id, d.id)
+		FROM users u
+		CROSS JOIN roles d
+		LEFT JOIN roles r ON r.name = u.role
+		WHERE d.name = ?
+		AND NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id)
+		ON CONFLICT DO NOTHING`, defaultRole)
+	return result.RowsAffected, result.Error
+}
@@ -1,16 +1,31 @@
 package repository
 
 import (
+	"time"
+
 	"eventix/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketRepository interface {
 	SaveBatch(tx *gorm.DB, tickets []entity.Ticket) error
 	FindByOrderID(orderID uint) ([]entity.Ticket, error)
+	FindByID(id uint) (*entity.Ticket, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Ticket, error)
+	FindByHolderID(holderID uint) ([]entity.Ticket, error)
 	FindByTicketCode(code string) (*entity.Ticket, error)
 	UpdateStatus(ticketID uint, status entity.TicketStatus) error
+	MarkAsUsed(ticketID uint, checkedInAt time.Time) error
+	MarkRefunded(tx *gorm.DB, ticketIDs []uint, refundID uint) error
+	Reissue(tx *gorm.DB, ticketID uint, oldCode, newCode string, holderID uint) error
+	UpdateCode(tx *gorm.DB, ticketID uint, code string) error
+	BackfillHolders() (int64, error)
+	CountByEventID(eventID uint, status entity.TicketStatus) (int64, error)
+	FindAttendeesByEventID(eventID uint) ([]entity.Attendee, error)
+	FindRevokedCodes(eventID uint) ([]entity.RevokedTicketCode, error)
+	GetDB() *gorm.DB
 }
 
 type ticketRepository struct {
@@ -36,9 +51,40 @@ func (r *ticketRepository) FindByOrderID(orderID uint) ([]entity.Ticket, error)
 	return tickets, nil
 }
 
+func (r *ticketRepository) FindByID(id uint) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := r.db.Preload("Event").Preload("Order").First(&ticket, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindByIDForUpdate loads a ticket with its order and event while holding a
+// row lock on the ticket until tx ends.
+func (r *ticketRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Event").Preload("Order").First(&ticket, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindByHolderID lists the tickets a user holds, whether bought or received,
+// leaving out refunded ones.
+func (r *ticketRepository) FindByHolderID(holderID uint) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	if err := r.db.Preload("Event").Preload("Seat").
+		Where("holder_id = ? AND status <> ?", holderID, entity.TicketStatusRefunded).
+		Order("created_at DESC").
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
 func (r *ticketRepository) FindByTicketCode(code string) (*entity.Ticket, error) {
 	var ticket entity.Ticket
-	if err := r.db.Preload("Event").Where("ticket_code = ?", code).First(&ticket).Error; err != nil {
+	if err := r.db.Preload("Event").Preload("Order").Where("ticket_code = ?", code).First(&ticket).Error; err != nil {
 		return nil, err
 	}
 	return &ticket, nil
@@ -47,3 +93,127 @@ func (r *ticketRepository) FindByTicketCode(code string) (*entity.Ticket, error)
 func (r *ticketRepository) UpdateStatus(ticketID uint, status entity.TicketStatus) error {
 	return r.db.Model(&entity.Ticket{}).Where("id = ?", ticketID).Update("status", status).Error
 }
+
+// MarkAsUsed flips a ticket from VALID to USED in a single guarded update so
+// that two scanners admitting the same ticket cannot both succeed.
+func (r *ticketRepository) MarkAsUsed(ticketID uint, checkedInAt time.Time) error {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND status = ?", ticketID, entity.TicketStatusValid).
+		Updates(map[string]interface{}{
+			"status":        entity.TicketStatusUsed,
+			"checked_in_at": checkedInAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// MarkRefunded flips the given tickets from VALID to REFUNDED. It returns
+// ErrNoRowsAffected unless every ticket was still valid, so a ticket scanned
+// at the gate meanwhile cannot also be refunded.
+func (r *ticketRepository) MarkRefunded(tx *gorm.DB, ticketIDs []uint, refundID uint) error {
+	result := tx.Model(&entity.Ticket{}).
+		Where("id IN ? AND status = ?", ticketIDs, entity.TicketStatusValid).
+		Updates(map[string]interface{}{
+			"status":    entity.TicketStatusRefunded,
+			"refund_id": refundID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(ticketIDs)) {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// Reissue gives a valid ticket a new code and holder. The old code must still
+// be current, so a ticket cannot be reissued twice from the same state.
+func (r *ticketRepository) Reissue(tx *gorm.DB, ticketID uint, oldCode, newCode string, holderID uint) error {
+	result := tx.Model(&entity.Ticket{}).
+		Where("id = ? AND ticket_code = ? AND status = ?", ticketID, oldCode, entity.TicketStatusValid).
+		Updates(map[string]interface{}{
+			"ticket_code": newCode,
+			"holder_id":   holderID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *ticketRepository) UpdateCode(tx *gorm.DB, ticketID uint, code string) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Model(&entity.Ticket{}).Where("id = ?", ticketID).Update("ticket_code", code).Error
+}
+
+// BackfillHolders makes buyers the holders of tickets issued before holders
+// were recorded and returns how many tickets were updated.
+func (r *ticketRepository) BackfillHolders() (int64, error) {
+	result := r.db.Exec(`
+		UPDATE tickets SET holder_id = orders.user_id
+		FROM orders
+		WHERE orders.id = tickets.order_id AND tickets.holder_id IS NULL`)
+	return result.RowsAffected, result.Error
+}
+
+// CountByEventID counts the tickets of an event, optionally narrowed to a status.
+func (r *ticketRepository) CountByEventID(eventID uint, status entity.TicketStatus) (int64, error) {
+	var count int64
+	query := r.db.Model(&entity.Ticket{}).Where("event_id = ?", eventID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindAttendeesByEventID lists the tickets of paid orders for an event together
+// with the holder's contact details. Refunded tickets are left out.
+func (r *ticketRepository) FindAttendeesByEventID(eventID uint) ([]entity.Attendee, error) {
+	var attendees []entity.Attendee
+	err := r.db.Model(&entity.Ticket{}).
+		Select(`tickets.id AS ticket_id, tickets.status, tickets.checked_in_at,
+			tickets.order_id, users.id AS user_id, users.name, users.email`).
+		Joins("JOIN orders ON orders.id = tickets.order_id").
+		Joins("JOIN users ON users.id = COALESCE(tickets.holder_id, orders.user_id)").
+		Where("tickets.event_id = ? AND tickets.status <> ?", eventID, entity.TicketStatusRefunded).
+		Where("orders.status IN ?", []entity.OrderStatus{entity.OrderStatusPaid, entity.OrderStatusPartiallyRefunded}).
+		Order("users.name ASC, tickets.id ASC").
+		Scan(&attendees).Error
+	return attendees, err
+}
+
+// FindRevokedCodes lists the codes of an event that no longer admit anyone:
+// those of refunded tickets and those replaced when a ticket was reissued.
+func (r *ticketRepository) FindRevokedCodes(eventID uint) ([]entity.RevokedTicketCode, error) {
+	var revoked []entity.RevokedTicketCode
+	err := r.db.Raw(`
+		SELECT tickets.id AS ticket_id, tickets.ticket_code, 'REFUNDED' AS reason, refunds.created_at AS revoked_at
+		FROM tickets
+		JOIN refunds ON refunds.id = tickets.refund_id
+		WHERE tickets.event_id = ? AND tickets.status = ?
+		UNION ALL
+		SELECT ticket_transfers.ticket_id, ticket_transfers.old_ticket_code, 'REISSUED', ticket_transfers.responded_at
+		FROM ticket_transfers
+		WHERE ticket_transfers.event_id = ? AND ticket_transfers.status = ?
+		ORDER BY revoked_at ASC`,
+		eventID, entity.TicketStatusRefunded, eventID, entity.TransferStatusAccepted,
+	).Scan(&revoked).Error
+	return revoked, err
+}
+
+func (r *ticketRepository) GetDB() *gorm.DB {
+	return r.db
+}
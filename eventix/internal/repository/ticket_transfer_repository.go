@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketTransferRepository interface {
+	Save(tx *gorm.DB, transfer *entity.TicketTransfer) error
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.TicketTransfer, error)
+	FindPendingByTicketID(tx *gorm.DB, ticketID uint) (*entity.TicketTransfer, error)
+	FindByTicketID(ticketID uint) ([]entity.TicketTransfer, error)
+	FindByUser(userID uint, email string) ([]entity.TicketTransfer, error)
+	Respond(tx *gorm.DB, id uint, status entity.TransferStatus, toUserID *uint, respondedAt time.Time) error
+}
+
+type ticketTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketTransferRepository(db *gorm.DB) TicketTransferRepository {
+	return &ticketTransferRepository{db: db}
+}
+
+func (r *ticketTransferRepository) Save(tx *gorm.DB, transfer *entity.TicketTransfer) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(transfer).Error
+}
+
+// FindByIDForUpdate loads a transfer while holding a row lock until tx ends.
+func (r *ticketTransferRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.TicketTransfer, error) {
+	var transfer entity.TicketTransfer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *ticketTransferRepository) FindPendingByTicketID(tx *gorm.DB, ticketID uint) (*entity.TicketTransfer, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var transfer entity.TicketTransfer
+	if err := tx.Where("ticket_id = ? AND status = ?", ticketID, entity.TransferStatusPending).First(&transfer).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByTicketID returns a ticket's full transfer history, oldest first.
+func (r *ticketTransferRepository) FindByTicketID(ticketID uint) ([]entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	if err := r.db.Where("ticket_id = ?", ticketID).Order("created_at ASC").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindByUser lists transfers the user sent or that were sent to their email.
+func (r *ticketTransferRepository) FindByUser(userID uint, email string) ([]entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	if err := r.db.Preload("Event").
+		Where("from_user_id = ? OR LOWER(to_email) = LOWER(?)", userID, email).
+		Order("created_at DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// Respond closes a pending transfer, returning ErrNoRowsAffected when it is
+// no longer pending.
+func (r *ticketTransferRepository) Respond(tx *gorm.DB, id uint, status entity.TransferStatus, toUserID *uint, respondedAt time.Time) error {
+	result := tx.Model(&entity.TicketTransfer{}).
+		Where("id = ? AND status = ?", id, entity.TransferStatusPending).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"to_user_id":   toUserID,
+			"responded_at": respondedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketTypeRepository interface {
+	Save(tx *gorm.DB, ticketType *entity.TicketType) error
+	Update(tx *gorm.DB, ticketType *entity.TicketType) error
+	Delete(tx *gorm.DB, id uint) error
+	FindByID(id uint) (*entity.TicketType, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.TicketType, error)
+	FindByEventID(eventID uint) ([]entity.TicketType, error)
+	FindByEventIDForUpdate(tx *gorm.DB, eventID uint) ([]entity.TicketType, error)
+	HasBookings(tx *gorm.DB, id uint) (bool, error)
+	DecrementAvailable(tx *gorm.DB, id uint, qty int) error
+	IncrementAvailable(tx *gorm.DB, id uint, qty int) error
+	BackfillDefaults() (int64, error)
+	GetDB() *gorm.DB
+}
+
+type ticketTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketTypeRepository(db *gorm.DB) TicketTypeRepository {
+	return &ticketTypeRepository{db: db}
+}
+
+func (r *ticketTypeRepository) Save(tx *gorm.DB, ticketType *entity.TicketType) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(ticketType).Error
+}
+
+func (r *ticketTypeRepository) Update(tx *gorm.DB, ticketType *entity.TicketType) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Save(ticketType).Error
+}
+
+func (r *ticketTypeRepository) Delete(tx *gorm.DB, id uint) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Delete(&entity.TicketType{}, id).Error
+}
+
+func (r *ticketTypeRepository) FindByID(id uint) (*entity.TicketType, error) {
+	var ticketType entity.TicketType
+	if err := r.db.First(&ticketType, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticketType, nil
+}
+
+// FindByIDForUpdate loads a ticket type while holding a row lock until tx ends.
+func (r *ticketTypeRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.TicketType, error) {
+	var ticketType entity.TicketType
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticketType, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticketType, nil
+}
+
+// FindByEventIDForUpdate loads all tiers of an event, locking them in ID order
+// until tx ends.
+func (r *ticketTypeRepository) FindByEventIDForUpdate(tx *gorm.DB, eventID uint) ([]entity.TicketType, error) {
+	var ticketTypes []entity.TicketType
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", eventID).
+		Order("id ASC").
+		Find(&ticketTypes).Error
+	return ticketTypes, err
+}
+
+func (r *ticketTypeRepository) FindByEventID(eventID uint) ([]entity.TicketType, error) {
+	var ticketTypes []entity.TicketType
+	err := r.db.Where("event_id = ?", eventID).Order("price ASC, id ASC").Find(&ticketTypes).Error
+	return ticketTypes, err
+}
+
+// HasBookings reports whether any order or hold has ever included the tier,
+// including orders since cancelled or refunded, whose tickets are back in stock.
+func (r *ticketTypeRepository) HasBookings(tx *gorm.DB, id uint) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var exists bool
+	err := tx.Raw(`
+		SELECT EXISTS (SELECT 1 FROM order_items WHERE ticket_type_id = ?)
+		OR EXISTS (SELECT 1 FROM hold_items WHERE ticket_type_id = ?)`, id, id).
+		Scan(&exists).Error
+	return exists, err
+}
+
+// DecrementAvailable reserves qty tickets of a tier with a conditional update.
+// It returns ErrNoRowsAffected when fewer than qty are left.
+func (r *ticketTypeRepository) DecrementAvailable(tx *gorm.DB, id uint, qty int) error {
+	result := tx.Model(&entity.TicketType{}).
+		Where("id = ? AND available_quantity >= ?", id, qty).
+		UpdateColumn("available_quantity", gorm.Expr("available_quantity - ?", qty))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *ticketTypeRepository) IncrementAvailable(tx *gorm.DB, id uint, qty int) error {
+	return tx.Model(&entity.TicketType{}).
+		Where("id = ?", id).
+		UpdateColumn("available_quantity", gorm.Expr("available_quantity + ?", qty)).Error
+}
+
+// BackfillDefaults moves data from before ticket tiers onto the tier model:
+// every event without tiers gets a General Admission tier mirroring its price
+// and inventory, and orders and tickets without a tier are attached to it. It
+// is idempotent and returns the number of tiers created.
+func (r *ticketTypeRepository) BackfillDefaults() (int64, error) {
+	var created int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			INSERT INTO ticket_types (event_id, name, description, price, total_quantity, available_quantity, max_per_order, created_at, updated_at)
+			SELECT e.id, ?, '', e.price, e.total_tickets, e.available_tickets, 0, NOW(), NOW()
+			FROM events e
+			WHERE NOT EXISTS (SELECT 1 FROM ticket_types t WHERE t.event_id = e.id)`,
+			entity.DefaultTicketTypeName)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected
+
+		if err := tx.Exec(`
+			INSERT INTO order_items (order_id, ticket_type_id, ticket_type_name, quantity, unit_price, subtotal, created_at)
+			SELECT o.id, t.id, t.name, o.quantity, o.total_amount / o.quantity, o.total_amount, o.created_at
+			FROM orders o
+			JOIN ticket_types t ON t.event_id = o.event_id AND t.name = ?
+			WHERE o.quantity > 0
+			AND NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id)`,
+			entity.DefaultTicketTypeName).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE tickets SET ticket_type_id = t.id
+			FROM ticket_types t
+			WHERE tickets.ticket_type_id IS NULL
+			AND t.event_id = tickets.event_id AND t.name = ?`,
+			entity.DefaultTicketTypeName).Error
+	})
+	return created, err
+}
+
+func (r *ticketTypeRepository) GetDB() *gorm.DB {
+	return r.db
+}
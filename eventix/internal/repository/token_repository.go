@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepository interface {
+	SaveRefreshToken(tx *gorm.DB, token *entity.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*entity.RefreshToken, error)
+	RevokeRefreshToken(tx *gorm.DB, id uint, replacedByID *uint) error
+	RevokeFamily(familyID string) error
+	DenyAccessToken(jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	PurgeExpired(now time.Time) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) SaveRefreshToken(tx *gorm.DB, token *entity.RefreshToken) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(token).Error
+}
+
+func (r *tokenRepository) FindRefreshTokenByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken marks a token as used. The update only matches tokens
+// that are still active, so of two concurrent refreshes with the same token
+// exactly one wins and the other sees ErrNoRowsAffected.
+func (r *tokenRepository) RevokeRefreshToken(tx *gorm.DB, id uint, replacedByID *uint) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": replacedByID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *tokenRepository) DenyAccessToken(jti string, expiresAt time.Time) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var token entity.RevokedToken
+	err := r.db.Select("jti").Where("jti = ?", jti).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// PurgeExpired deletes denylist entries and refresh tokens that can no longer
+// be presented.
+func (r *tokenRepository) PurgeExpired(now time.Time) (int64, error) {
+	denied := r.db.Where("expires_at < ?", now).Delete(&entity.RevokedToken{})
+	if denied.Error != nil {
+		return 0, denied.Error
+	}
+
+	refresh := r.db.Where("expires_at < ?", now).Delete(&entity.RefreshToken{})
+	if refresh.Error != nil {
+		return denied.RowsAffected, refresh.Error
+	}
+
+	return denied.RowsAffected + refresh.RowsAffected, nil
+}
+
+func (r *tokenRepository) GetDB() *gorm.DB {
+	return r.db
+}
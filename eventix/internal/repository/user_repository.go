@@ -4,11 +4,15 @@ import (
 	"eventix/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	Save(user *entity.User) error
+	Save(tx *gorm.DB, user *entity.User) error
 	FindByEmail(email string) (*entity.User, error)
+	FindByID(id uint) (*entity.User, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.User, error)
+	GetDB() *gorm.DB
 }
 
 type userRepository struct {
@@ -19,8 +23,11 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Save(user *entity.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Save(tx *gorm.DB, user *entity.User) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(user).Error
 }
 
 func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
@@ -31,3 +38,24 @@ func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) FindByID(id uint) (*entity.User, error) {
+	var user entity.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByIDForUpdate loads a user while holding a row lock until tx ends.
+func (r *userRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.User, error) {
+	var user entity.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type VenueRepository interface {
+	Save(venue *entity.Venue) error
+	FindByID(id uint) (*entity.Venue, error)
+	FindAll() ([]entity.Venue, error)
+	FindSeatsBySectionIDs(sectionIDs []uint) ([]entity.Seat, error)
+}
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+// Save inserts the venue together with its sections and seats.
+func (r *venueRepository) Save(venue *entity.Venue) error {
+	return r.db.Create(venue).Error
+}
+
+func (r *venueRepository) FindByID(id uint) (*entity.Venue, error) {
+	var venue entity.Venue
+	err := r.db.
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("venue_sections.id ASC")
+		}).
+		Preload("Sections.Seats", func(db *gorm.DB) *gorm.DB {
+			return db.Order("seats.id ASC")
+		}).
+		First(&venue, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) FindAll() ([]entity.Venue, error) {
+	var venues []entity.Venue
+	if err := r.db.Order("name ASC").Find(&venues).Error; err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+func (r *venueRepository) FindSeatsBySectionIDs(sectionIDs []uint) ([]entity.Seat, error) {
+	var seats []entity.Seat
+	if err := r.db.Where("section_id IN ?", sectionIDs).Order("id ASC").Find(&seats).Error; err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"time"
+
+	"eventix/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WaitlistRepository interface {
+	Save(tx *gorm.DB, entry *entity.WaitlistEntry) error
+	FindByID(id uint) (*entity.WaitlistEntry, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.WaitlistEntry, error)
+	FindByUserID(userID uint) ([]entity.WaitlistEntry, error)
+	FindActive(userID, ticketTypeID uint) (*entity.WaitlistEntry, error)
+	FindWaitingForUpdate(tx *gorm.DB, ticketTypeID uint, limit int) ([]entity.WaitlistEntry, error)
+	FindExpiredOffers(now time.Time, limit int) ([]entity.WaitlistEntry, error)
+	FindTicketTypesToOffer(limit int) ([]uint, error)
+	CountAhead(entry *entity.WaitlistEntry) (int64, error)
+	HasActive(tx *gorm.DB, ticketTypeID uint) (bool, error)
+	CountOfferedTicketsByUser(tx *gorm.DB, userID, eventID uint) (int64, error)
+	MarkOffered(tx *gorm.DB, id uint, offeredAt, expiresAt time.Time) error
+	MarkClaimed(tx *gorm.DB, id uint, orderID uint) error
+	TransitionStatus(tx *gorm.DB, id uint, from, to entity.WaitlistStatus) error
+	GetDB() *gorm.DB
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) Save(tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(entry).Error
+}
+
+func (r *waitlistRepository) FindByID(id uint) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	if err := r.db.Preload("Event").Preload("TicketType").First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByIDForUpdate loads an entry while holding a row lock until tx ends.
+func (r *waitlistRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) FindByUserID(userID uint) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.Preload("Event").Preload("TicketType").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindActive returns the user's WAITING or OFFERED entry for a ticket type.
+func (r *waitlistRepository) FindActive(userID, ticketTypeID uint) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := r.db.Where("user_id = ? AND ticket_type_id = ? AND status IN ?", userID, ticketTypeID,
+		[]entity.WaitlistStatus{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindWaitingForUpdate locks the first waiting entries of a ticket type in
+// line order. Entries locked by another transaction, e.g. a user leaving the
+// waitlist, are skipped.
+func (r *waitlistRepository) FindWaitingForUpdate(tx *gorm.DB, ticketTypeID uint, limit int) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("ticket_type_id = ? AND status = ?", ticketTypeID, entity.WaitlistStatusWaiting).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) FindExpiredOffers(now time.Time, limit int) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.
+		Where("status = ? AND offer_expires_at <= ?", entity.WaitlistStatusOffered, now).
+		Order("offer_expires_at ASC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindTicketTypesToOffer returns ticket types that have tickets left while
+// people are still waiting for them, e.g. after an organizer added inventory.
+// Seated tiers are booked by seat and never offered.
+func (r *waitlistRepository) FindTicketTypesToOffer(limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&entity.TicketType{}).
+		Joins("JOIN events ON events.id = ticket_types.event_id").
+		Where("ticket_types.available_quantity > 0 AND events.available_tickets > 0 AND NOT ticket_types.seated").
+		Where("EXISTS (SELECT 1 FROM waitlist_entries w WHERE w.ticket_type_id = ticket_types.id AND w.status = ?)", entity.WaitlistStatusWaiting).
+		Order("ticket_types.id ASC").
+		Limit(limit).
+		Pluck("ticket_types.id", &ids).Error
+	return ids, err
+}
+
+// CountAhead counts waiting entries for the same ticket type that joined
+// before entry.
+func (r *waitlistRepository) CountAhead(entry *entity.WaitlistEntry) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.WaitlistEntry{}).
+		Where("ticket_type_id = ? AND status = ? AND (created_at < ? OR (created_at = ? AND id < ?))",
+			entry.TicketTypeID, entity.WaitlistStatusWaiting, entry.CreatedAt, entry.CreatedAt, entry.ID).
+		Count(&count).Error
+	return count, err
+}
+
+// HasActive reports whether anyone is waiting for the ticket type or holds an
+// open offer for it.
+func (r *waitlistRepository) HasActive(tx *gorm.DB, ticketTypeID uint) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var count int64
+	err := tx.Model(&entity.WaitlistEntry{}).
+		Where("ticket_type_id = ? AND status IN ?", ticketTypeID,
+			[]entity.WaitlistStatus{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountOfferedTicketsByUser counts the tickets held for a user's open offers
+// for an event, including offers that ran out but were not expired yet.
+func (r *waitlistRepository) CountOfferedTicketsByUser(tx *gorm.DB, userID, eventID uint) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var offered int64
+	err := tx.Model(&entity.WaitlistEntry{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("user_id = ? AND event_id = ? AND status = ?", userID, eventID, entity.WaitlistStatusOffered).
+		Scan(&offered).Error
+	return offered, err
+}
+
+func (r *waitlistRepository) MarkOffered(tx *gorm.DB, id uint, offeredAt, expiresAt time.Time) error {
+	result := tx.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, entity.WaitlistStatusWaiting).
+		Updates(map[string]interface{}{
+			"status":           entity.WaitlistStatusOffered,
+			"offered_at":       offeredAt,
+			"offer_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *waitlistRepository) MarkClaimed(tx *gorm.DB, id uint, orderID uint) error {
+	result := tx.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, entity.WaitlistStatusOffered).
+		Updates(map[string]interface{}{
+			"status":   entity.WaitlistStatusClaimed,
+			"order_id": orderID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+// TransitionStatus moves an entry to a new status only if it is still in the
+// expected one, returning ErrNoRowsAffected otherwise.
+func (r *waitlistRepository) TransitionStatus(tx *gorm.DB, id uint, from, to entity.WaitlistStatus) error {
+	if tx == nil {
+		tx = r.db
+	}
+	result := tx.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
+func (r *waitlistRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"log"
+	"time"
 
 	"eventix/internal/entity"
 	"eventix/internal/repository"
@@ -15,25 +17,40 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrEmailAlreadyExists = errors.New("email already registered")
+	ErrInvalidToken       = errors.New("invalid or expired refresh token")
+	ErrTokenReused        = errors.New("refresh token reuse detected")
 )
 
 // AuthService defines the interface for authentication operations.
-// It abstracts the business logic for user registration and login.
+// It abstracts the business logic for user registration, login and the
+// lifecycle of issued tokens.
 type AuthService interface {
 	// Register creates a new user account with hashed password
 	Register(input *entity.RegisterInput) (*entity.User, error)
-	// Login authenticates a user and returns a JWT token
-	Login(input *entity.LoginInput) (string, error)
+	// Login authenticates a user and returns an access/refresh token pair
+	Login(input *entity.LoginInput) (*entity.TokenPair, error)
+	// Refresh rotates a refresh token and returns a new token pair
+	Refresh(refreshToken string) (*entity.TokenPair, error)
+	// Logout revokes the current access token and, if given, the refresh token family
+	Logout(userID uint, jti string, expiresAt time.Time, refreshToken string) error
+	// PurgeExpiredTokens deletes denylist entries and refresh tokens that have expired
+	PurgeExpiredTokens() (int64, error)
 }
 
 // authService is the implementation of AuthService.
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	roleRepo  repository.RoleRepository
+	tokenRepo repository.TokenRepository
 }
 
 // NewAuthService creates a new instance of AuthService.
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepo}
+func NewAuthService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, tokenRepo repository.TokenRepository) AuthService {
+	return &authService{
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		tokenRepo: tokenRepo,
+	}
 }
 
 // Register creates a new user account.
@@ -42,6 +59,7 @@ func NewAuthService(userRepo repository.UserRepository) AuthService {
 // 2. Hash the password using bcrypt
 // 3. Create the user entity
 // 4. Save to database via repository
+// 5. Grant the default role
 func (s *authService) Register(input *entity.RegisterInput) (*entity.User, error) {
 	// Step 1: Check if user with this email already exists
 	existingUser, err := s.userRepo.FindByEmail(input.Email)
@@ -64,44 +82,246 @@ func (s *authService) Register(input *entity.RegisterInput) (*entity.User, error
 		Name:     input.Name,
 		Email:    input.Email,
 		Password: hashedPassword,
-		Role:     "user", // Default role for new users
 	}
 
-	// Step 4: Save user to database
-	if err := s.userRepo.Save(user); err != nil {
+	role, err := s.roleRepo.FindByName(entity.RoleUser)
+	if err != nil {
 		return nil, err
 	}
 
+	// Step 4: Save the user together with the default role, so a failure
+	// cannot leave an account without any role
+	tx := s.userRepo.GetDB().Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.userRepo.Save(tx, user); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.roleRepo.AssignToUser(tx, user.ID, role.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	user.Roles = []entity.Role{*role}
+
 	return user, nil
 }
 
-// Login authenticates a user and returns a JWT token.
+// Login authenticates a user and returns a token pair.
 // Flow:
 // 1. Find user by email
 // 2. Verify password using bcrypt
-// 3. Generate JWT token with user claims
-// 4. Return the token
-func (s *authService) Login(input *entity.LoginInput) (string, error) {
+// 3. Start a new refresh token family and issue the token pair
+func (s *authService) Login(input *entity.LoginInput) (*entity.TokenPair, error) {
 	// Step 1: Find user by email
 	user, err := s.userRepo.FindByEmail(input.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", ErrInvalidCredentials
+			return nil, ErrInvalidCredentials
 		}
-		return "", err
+		return nil, err
 	}
 
 	// Step 2: Verify password matches the stored hash
 	if err := utils.CheckPassword(user.Password, input.Password); err != nil {
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
+	}
+
+	// Step 3: Every login starts its own family so logging out one device
+	// does not sign out the others
+	familyID, err := utils.GenerateTokenID()
+	if err != nil {
+		return nil, err
 	}
 
-	// Step 3: Generate JWT token with user ID and role
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	refreshToken, _, err := s.issueRefreshToken(nil, user.ID, familyID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return s.newTokenPair(user, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+// Flow:
+// 1. Look up the token by its hash
+// 2. Presenting a rotated token means it leaked: revoke the whole family
+// 3. Rotate: revoke the old token and issue its replacement atomically
+// 4. Sign a new access token with the user's current roles
+func (s *authService) Refresh(refreshToken string) (*entity.TokenPair, error) {
+	// Step 1: Look up the presented token
+	current, err := s.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Step 2: Reuse detection
+	if current.RevokedAt != nil {
+		s.revokeFamily(current)
+		return nil, ErrTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Step 3: Rotate inside a transaction
+	db := s.tokenRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	newToken, replacement, err := s.issueRefreshToken(tx, user.ID, current.FamilyID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.tokenRepo.RevokeRefreshToken(tx, current.ID, &replacement.ID); err != nil {
+		tx.Rollback()
+		// A concurrent refresh already rotated this token: treat as reuse
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			s.revokeFamily(current)
+			return nil, ErrTokenReused
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Step 4: Re-read the roles so demotions take effect on the next refresh
+	return s.newTokenPair(user, newToken)
+}
+
+// Logout denylists the access token until it expires and revokes the refresh
+// token family it was issued with. Unknown or foreign refresh tokens are
+// ignored so logout never leaks whether a token exists.
+func (s *authService) Logout(userID uint, jti string, expiresAt time.Time, refreshToken string) error {
+	if jti != "" {
+		if err := s.tokenRepo.DenyAccessToken(jti, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if token.UserID != userID {
+		return nil
+	}
+
+	return s.tokenRepo.RevokeFamily(token.FamilyID)
+}
+
+func (s *authService) PurgeExpiredTokens() (int64, error) {
+	return s.tokenRepo.PurgeExpired(time.Now())
+}
+
+// issueRefreshToken creates a refresh token in the given family and returns
+// the plaintext token together with its stored record.
+func (s *authService) issueRefreshToken(tx *gorm.DB, userID uint, familyID string) (string, *entity.RefreshToken, error) {
+	plain, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := &entity.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(plain),
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL()),
+	}
+	if err := s.tokenRepo.SaveRefreshToken(tx, token); err != nil {
+		return "", nil, err
+	}
+
+	return plain, token, nil
+}
+
+func (s *authService) newTokenPair(user *entity.User, refreshToken string) (*entity.TokenPair, error) {
+	roles, err := s.roleRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames, permissions := flattenRoles(roles)
+	accessToken, err := utils.GenerateToken(user.ID, roleNames, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+func (s *authService) revokeFamily(token *entity.RefreshToken) {
+	log.Printf("[Auth] Refresh token reuse detected for user %d, revoking family %s", token.UserID, token.FamilyID)
+	if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		log.Printf("[Auth] Failed to revoke token family %s: %v", token.FamilyID, err)
+	}
+}
+
+// flattenRoles returns the role names and the de-duplicated permissions they
+// grant.
+func flattenRoles(roles []entity.Role) ([]string, []string) {
+	names := make([]string, 0, len(roles))
+	permissions := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, permission := range role.Permissions {
+			if seen[permission.Name] {
+				continue
+			}
+			seen[permission.Name] = true
+			permissions = append(permissions, permission.Name)
+		}
 	}
 
-	// Step 4: Return the generated token
-	return token, nil
+	return names, permissions
 }
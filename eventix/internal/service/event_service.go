@@ -2,40 +2,122 @@ package service
 
 import (
 	"errors"
+	"log"
+	"strings"
+	"time"
 
 	"eventix/internal/entity"
 	"eventix/internal/repository"
 )
 
 var (
-	ErrEventNotFound = errors.New("event not found")
+	ErrEventNotFound  = errors.New("event not found")
+	ErrEventForbidden = errors.New("not allowed to manage this event")
+
+	ErrTicketTypeNameTaken = errors.New("event already has a ticket type with this name")
+	ErrTicketTypeInUse     = errors.New("ticket type already has bookings")
+	ErrLastTicketType      = errors.New("an event must keep at least one ticket type")
+	ErrTicketTypeBelowSold = errors.New("quantity is below the number already booked")
+	ErrInvalidSalesWindow  = errors.New("sales start must be before sales end")
+
+	ErrEventCapacityRequired = errors.New("total tickets are required for events without ticket types")
+	ErrEventHasTicketTypes   = errors.New("event has several ticket types, update their quantity and price instead")
 )
 
+// EventService manages events. Mutations and organizer views take the acting
+// user: organizers may only touch events they created, while holders of
+// events:manage_all may touch any event.
 type EventService interface {
-	CreateEvent(input *entity.CreateEventInput) (*entity.Event, error)
+	CreateEvent(actor entity.Actor, input *entity.CreateEventInput) (*entity.Event, error)
 	GetAllEvents(filter entity.EventFilter) ([]entity.Event, int64, error)
 	GetEventByID(id uint) (*entity.Event, error)
-	UpdateEvent(id uint, input *entity.UpdateEventInput) (*entity.Event, error)
-	DeleteEvent(id uint) error
+	GetOrganizerEvents(actor entity.Actor, filter entity.EventFilter) ([]entity.Event, int64, error)
+	UpdateEvent(actor entity.Actor, id uint, input *entity.UpdateEventInput) (*entity.Event, error)
+	DeleteEvent(actor entity.Actor, id uint) error
+	// SetPurchaseLimit overrides the per-user ticket limit; callers must hold events:manage_all
+	SetPurchaseLimit(id uint, input *entity.PurchaseLimitInput) (*entity.Event, error)
+	GetEventOrders(actor entity.Actor, eventID uint, filter entity.OrderFilter) ([]entity.Order, int64, error)
+	GetEventAttendees(actor entity.Actor, eventID uint) ([]entity.Attendee, error)
+	ListTicketTypes(eventID uint) ([]entity.TicketType, error)
+	CreateTicketType(actor entity.Actor, eventID uint, input *entity.CreateTicketTypeInput) (*entity.TicketType, error)
+	UpdateTicketType(actor entity.Actor, eventID, ticketTypeID uint, input *entity.UpdateTicketTypeInput) (*entity.TicketType, error)
+	DeleteTicketType(actor entity.Actor, eventID, ticketTypeID uint) error
+	// EnsureTicketTypes moves events and orders from before ticket tiers onto a default tier
+	EnsureTicketTypes() error
 }
 
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo      repository.EventRepository
+	ticketTypeRepo repository.TicketTypeRepository
+	orderRepo      repository.OrderRepository
+	ticketRepo     repository.TicketRepository
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
-	return &eventService{eventRepo: eventRepo}
+func NewEventService(
+	eventRepo repository.EventRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	orderRepo repository.OrderRepository,
+	ticketRepo repository.TicketRepository,
+) EventService {
+	return &eventService{
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		orderRepo:      orderRepo,
+		ticketRepo:     ticketRepo,
+	}
 }
 
-func (s *eventService) CreateEvent(input *entity.CreateEventInput) (*entity.Event, error) {
+// CreateEvent records the acting user as the event's organizer and creates its
+// ticket tiers in the same insert. Without explicit tiers the event gets a
+// single General Admission tier matching its price and capacity; with tiers it
+// is listed at the price of the cheapest one.
+func (s *eventService) CreateEvent(actor entity.Actor, input *entity.CreateEventInput) (*entity.Event, error) {
+	ticketTypes := make([]entity.TicketType, 0, len(input.TicketTypes))
+	seen := make(map[string]bool)
+	for i := range input.TicketTypes {
+		ticketType, err := newTicketType(&input.TicketTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(ticketType.Name)
+		if seen[key] {
+			return nil, ErrTicketTypeNameTaken
+		}
+		seen[key] = true
+		ticketTypes = append(ticketTypes, *ticketType)
+	}
+	if len(ticketTypes) == 0 {
+		if input.TotalTickets == 0 {
+			return nil, ErrEventCapacityRequired
+		}
+		ticketTypes = append(ticketTypes, entity.TicketType{
+			Name:              entity.DefaultTicketTypeName,
+			Price:             input.Price,
+			TotalQuantity:     input.TotalTickets,
+			AvailableQuantity: input.TotalTickets,
+		})
+	}
+
+	capacity := 0
+	price := ticketTypes[0].Price
+	for _, ticketType := range ticketTypes {
+		capacity += ticketType.TotalQuantity
+		if ticketType.Price < price {
+			price = ticketType.Price
+		}
+	}
+
+	organizerID := actor.UserID
 	event := &entity.Event{
 		Title:            input.Title,
 		Description:      input.Description,
 		Date:             input.Date,
 		Location:         input.Location,
-		TotalTickets:     input.TotalTickets,
-		AvailableTickets: input.TotalTickets,
-		Price:            input.Price,
+		TotalTickets:     capacity,
+		AvailableTickets: capacity,
+		Price:            price,
+		OrganizerID:      &organizerID,
+		TicketTypes:      ticketTypes,
 	}
 
 	if err := s.eventRepo.Save(event); err != nil {
@@ -57,9 +139,51 @@ func (s *eventService) GetEventByID(id uint) (*entity.Event, error) {
 	return event, nil
 }
 
-func (s *eventService) UpdateEvent(id uint, input *entity.UpdateEventInput) (*entity.Event, error) {
-	event, err := s.eventRepo.FindByID(id)
+// GetOrganizerEvents lists the events created by the acting user.
+func (s *eventService) GetOrganizerEvents(actor entity.Actor, filter entity.EventFilter) ([]entity.Event, int64, error) {
+	filter.OrganizerID = actor.UserID
+	return s.eventRepo.FindAll(filter)
+}
+
+// UpdateEvent applies the changes under a row lock so that resizing the ticket
+// pool cannot overwrite decrements made by concurrent bookings. Capacity and
+// price belong to the tiers, so they are forwarded to the event's only tier.
+func (s *eventService) UpdateEvent(actor entity.Actor, id uint, input *entity.UpdateEventInput) (*entity.Event, error) {
+	current, err := s.findManagedEvent(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changesTier := input.TotalTickets > 0 || input.Price > 0
+	if changesTier && len(current.TicketTypes) != 1 {
+		return nil, ErrEventHasTicketTypes
+	}
+
+	db := s.eventRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Tiers are locked before their event, in the order bookings lock them
+	var ticketType *entity.TicketType
+	if changesTier {
+		ticketType, err = s.ticketTypeRepo.FindByIDForUpdate(tx, current.TicketTypes[0].ID)
+		if err != nil {
+			tx.Rollback()
+			return nil, ErrTicketTypeNotFound
+		}
+	}
+
+	event, err := s.eventRepo.FindByIDForUpdate(tx, id)
 	if err != nil {
+		tx.Rollback()
 		return nil, ErrEventNotFound
 	}
 
@@ -76,28 +200,449 @@ func (s *eventService) UpdateEvent(id uint, input *entity.UpdateEventInput) (*en
 		event.Location = input.Location
 	}
 	if input.TotalTickets > 0 {
-		diff := input.TotalTickets - event.TotalTickets
-		event.TotalTickets = input.TotalTickets
-		event.AvailableTickets += diff
-		if event.AvailableTickets < 0 {
-			event.AvailableTickets = 0
+		delta, err := resizeTicketType(ticketType, input.TotalTickets)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
 		}
+		event.TotalTickets += delta
+		event.AvailableTickets += delta
 	}
 	if input.Price > 0 {
+		ticketType.Price = input.Price
 		event.Price = input.Price
 	}
 
-	if err := s.eventRepo.Update(event); err != nil {
+	if ticketType != nil {
+		if err := s.ticketTypeRepo.Update(tx, ticketType); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := s.eventRepo.Update(tx, event); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
 
 	return event, nil
 }
 
-func (s *eventService) DeleteEvent(id uint) error {
-	_, err := s.eventRepo.FindByID(id)
-	if err != nil {
-		return ErrEventNotFound
+func (s *eventService) DeleteEvent(actor entity.Actor, id uint) error {
+	if _, err := s.findManagedEvent(actor, id); err != nil {
+		return err
 	}
 	return s.eventRepo.Delete(id)
 }
+
+func (s *eventService) SetPurchaseLimit(id uint, input *entity.PurchaseLimitInput) (*entity.Event, error) {
+	if err := s.eventRepo.UpdatePurchaseLimit(id, input.MaxTicketsPerUser); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	return s.GetEventByID(id)
+}
+
+func (s *eventService) GetEventOrders(actor entity.Actor, eventID uint, filter entity.OrderFilter) ([]entity.Order, int64, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, 0, err
+	}
+
+	filter.EventID = eventID
+	return s.orderRepo.FindAll(filter)
+}
+
+func (s *eventService) GetEventAttendees(actor entity.Actor, eventID uint) ([]entity.Attendee, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	return s.ticketRepo.FindAttendeesByEventID(eventID)
+}
+
+func (s *eventService) findManagedEvent(actor entity.Actor, id uint) (*entity.Event, error) {
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if !canManageEvent(actor, event) {
+		return nil, ErrEventForbidden
+	}
+	return event, nil
+}
+
+// canManageEvent reports whether actor organizes the event or may manage all
+// events. Events created before ownership was tracked have no organizer and
+// can only be managed by the latter.
+func canManageEvent(actor entity.Actor, event *entity.Event) bool {
+	if actor.Can(entity.PermissionEventsManageAll) {
+		return true
+	}
+	return event.OrganizerID != nil && *event.OrganizerID == actor.UserID
+}
+
+func (s *eventService) ListTicketTypes(eventID uint) ([]entity.TicketType, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+	return s.ticketTypeRepo.FindByEventID(eventID)
+}
+
+func (s *eventService) CreateTicketType(actor entity.Actor, eventID uint, input *entity.CreateTicketTypeInput) (*entity.TicketType, error) {
+	event, err := s.findManagedEvent(actor, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketType, err := newTicketType(input)
+	if err != nil {
+		return nil, err
+	}
+	if hasTicketTypeNamed(event.TicketTypes, ticketType.Name, 0) {
+		return nil, ErrTicketTypeNameTaken
+	}
+
+	db := s.ticketTypeRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ticketType.EventID = event.ID
+	if err := s.ticketTypeRepo.Save(tx, ticketType); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.eventRepo.AdjustCapacity(tx, event.ID, ticketType.TotalQuantity); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.eventRepo.SyncPrice(tx, event.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+// UpdateTicketType edits a tier under a row lock. Resizing keeps the number of
+// tickets already booked, so the quantity cannot drop below it, and resizes the
+// event's pool by the same amount; repricing updates the event's listed price.
+func (s *eventService) UpdateTicketType(actor entity.Actor, eventID, ticketTypeID uint, input *entity.UpdateTicketTypeInput) (*entity.TicketType, error) {
+	event, err := s.findManagedEvent(actor, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != "" && hasTicketTypeNamed(event.TicketTypes, input.Name, ticketTypeID) {
+		return nil, ErrTicketTypeNameTaken
+	}
+
+	db := s.ticketTypeRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ticketType, err := s.ticketTypeRepo.FindByIDForUpdate(tx, ticketTypeID)
+	if err != nil || ticketType.EventID != event.ID {
+		tx.Rollback()
+		return nil, ErrTicketTypeNotFound
+	}
+
+	if input.Name != "" {
+		ticketType.Name = input.Name
+	}
+	if input.Description != "" {
+		ticketType.Description = input.Description
+	}
+	if input.Price != nil {
+		ticketType.Price = *input.Price
+	}
+	if input.SalesStart != nil {
+		ticketType.SalesStart = input.SalesStart
+	}
+	if input.SalesEnd != nil {
+		ticketType.SalesEnd = input.SalesEnd
+	}
+	if input.MaxPerOrder != nil {
+		ticketType.MaxPerOrder = *input.MaxPerOrder
+	}
+	delta := 0
+	if input.TotalQuantity > 0 {
+		delta, err = resizeTicketType(ticketType, input.TotalQuantity)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if !validSalesWindow(ticketType.SalesStart, ticketType.SalesEnd) {
+		tx.Rollback()
+		return nil, ErrInvalidSalesWindow
+	}
+
+	if err := s.ticketTypeRepo.Update(tx, ticketType); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if delta != 0 {
+		if err := s.eventRepo.AdjustCapacity(tx, event.ID, delta); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if input.Price != nil {
+		if err := s.eventRepo.SyncPrice(tx, event.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return ticketType, nil
+}
+
+// DeleteTicketType removes a tier that has never been booked, along with its
+// tickets in the event's pool. All tiers of the event are locked so that two
+// deletions cannot together remove the last one.
+func (s *eventService) DeleteTicketType(actor entity.Actor, eventID, ticketTypeID uint) error {
+	event, err := s.findManagedEvent(actor, eventID)
+	if err != nil {
+		return err
+	}
+
+	db := s.ticketTypeRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ticketTypes, err := s.ticketTypeRepo.FindByEventIDForUpdate(tx, event.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var ticketType *entity.TicketType
+	for i := range ticketTypes {
+		if ticketTypes[i].ID == ticketTypeID {
+			ticketType = &ticketTypes[i]
+		}
+	}
+	if ticketType == nil {
+		tx.Rollback()
+		return ErrTicketTypeNotFound
+	}
+
+	if len(ticketTypes) == 1 {
+		tx.Rollback()
+		return ErrLastTicketType
+	}
+
+	// Tickets sold or held are missing from the inventory; ones that came back
+	// from cancelled or refunded orders are still referenced by those orders
+	if ticketType.AvailableQuantity != ticketType.TotalQuantity {
+		tx.Rollback()
+		return ErrTicketTypeInUse
+	}
+	booked, err := s.ticketTypeRepo.HasBookings(tx, ticketType.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if booked {
+		tx.Rollback()
+		return ErrTicketTypeInUse
+	}
+
+	if err := s.ticketTypeRepo.Delete(tx, ticketType.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.eventRepo.AdjustCapacity(tx, event.ID, -ticketType.TotalQuantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.eventRepo.SyncPrice(tx, event.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *eventService) EnsureTicketTypes() error {
+	created, err := s.ticketTypeRepo.BackfillDefaults()
+	if err != nil {
+		return err
+	}
+	if created > 0 {
+		log.Printf("[Events] Created default ticket types for %d event(s)", created)
+	}
+
+	drifted, err := s.eventRepo.FindCapacityDrift()
+	if err != nil {
+		return err
+	}
+	synced := 0
+	for _, eventID := range drifted {
+		changed, err := s.syncCapacity(eventID)
+		if err != nil {
+			return err
+		}
+		if changed {
+			synced++
+		}
+	}
+	if synced > 0 {
+		log.Printf("[Events] Matched the ticket pool of %d event(s) to their ticket types", synced)
+	}
+
+	repriced, err := s.eventRepo.SyncPrices()
+	if err != nil {
+		return err
+	}
+	if repriced > 0 {
+		log.Printf("[Events] Matched the price of %d event(s) to their cheapest ticket type", repriced)
+	}
+	return nil
+}
+
+// syncCapacity resets an event's ticket pool to the sums over its tiers. The
+// tiers and then the event are locked, in the order bookings lock them, so a
+// concurrent booking cannot be overwritten. It reports whether the pool changed.
+func (s *eventService) syncCapacity(eventID uint) (bool, error) {
+	db := s.eventRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ticketTypes, err := s.ticketTypeRepo.FindByEventIDForUpdate(tx, eventID)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if len(ticketTypes) == 0 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	event, err := s.eventRepo.FindByIDForUpdate(tx, eventID)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	total, available := 0, 0
+	for _, ticketType := range ticketTypes {
+		total += ticketType.TotalQuantity
+		available += ticketType.AvailableQuantity
+	}
+	if event.TotalTickets == total && event.AvailableTickets == available {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if err := s.eventRepo.SetCapacity(tx, eventID, total, available); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func newTicketType(input *entity.CreateTicketTypeInput) (*entity.TicketType, error) {
+	if !validSalesWindow(input.SalesStart, input.SalesEnd) {
+		return nil, ErrInvalidSalesWindow
+	}
+
+	return &entity.TicketType{
+		Name:              input.Name,
+		Description:       input.Description,
+		Price:             input.Price,
+		TotalQuantity:     input.TotalQuantity,
+		AvailableQuantity: input.TotalQuantity,
+		SalesStart:        input.SalesStart,
+		SalesEnd:          input.SalesEnd,
+		MaxPerOrder:       input.MaxPerOrder,
+	}, nil
+}
+
+// resizeTicketType sets a tier's quantity while keeping the tickets already
+// booked, and returns by how much its inventory changed.
+func resizeTicketType(ticketType *entity.TicketType, total int) (int, error) {
+	if total == ticketType.TotalQuantity {
+		return 0, nil
+	}
+	if ticketType.Seated {
+		return 0, ErrSeatedTicketTypeResize
+	}
+	booked := ticketType.TotalQuantity - ticketType.AvailableQuantity
+	if total < booked {
+		return 0, ErrTicketTypeBelowSold
+	}
+
+	delta := total - ticketType.TotalQuantity
+	ticketType.TotalQuantity = total
+	ticketType.AvailableQuantity += delta
+	return delta, nil
+}
+
+func validSalesWindow(start, end *time.Time) bool {
+	return start == nil || end == nil || start.Before(*end)
+}
+
+// hasTicketTypeNamed reports whether another tier than exceptID already uses name.
+func hasTicketTypeNamed(ticketTypes []entity.TicketType, name string, exceptID uint) bool {
+	for _, ticketType := range ticketTypes {
+		if ticketType.ID != exceptID && strings.EqualFold(ticketType.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHoldNotFound        = errors.New("hold not found")
+	ErrHoldNotActive       = errors.New("hold is no longer active")
+	ErrHoldExpired         = errors.New("hold has expired")
+	ErrHoldAlreadyExtended = errors.New("hold can only be extended once")
+)
+
+// holdExpiryBatchSize caps how many holds a single reaper pass expires.
+const holdExpiryBatchSize = 100
+
+// HoldService puts tickets in a time-limited cart before an order exists.
+type HoldService interface {
+	CreateHold(userID, eventID uint, input *entity.CreateHoldInput) (*entity.Hold, error)
+	GetUserHolds(userID uint) ([]entity.Hold, error)
+	GetHold(userID, holdID uint) (*entity.Hold, error)
+	ExtendHold(userID, holdID uint) (*entity.Hold, error)
+	ReleaseHold(userID, holdID uint) error
+	CheckoutHold(userID, holdID uint, input *entity.CheckoutHoldInput) (*entity.Order, error)
+	ExpireHolds() (int, error)
+}
+
+type holdService struct {
+	holdRepo      repository.HoldRepository
+	orderRepo     repository.OrderRepository
+	eventRepo     repository.EventRepository
+	promoCodeRepo repository.PromoCodeRepository
+	eventSeatRepo repository.EventSeatRepository
+	inventory     *inventoryManager
+	waitlist      *waitlistOfferer
+	limits        *purchaseLimiter
+	holdWindow    time.Duration
+	extension     time.Duration
+	paymentWindow time.Duration
+}
+
+func NewHoldService(
+	holdRepo repository.HoldRepository,
+	orderRepo repository.OrderRepository,
+	eventRepo repository.EventRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	promoCodeRepo repository.PromoCodeRepository,
+	waitlistRepo repository.WaitlistRepository,
+	eventSeatRepo repository.EventSeatRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	holdWindow time.Duration,
+	extension time.Duration,
+	paymentWindow time.Duration,
+	waitlistOfferWindow time.Duration,
+	maxTicketsPerUser int,
+) HoldService {
+	inventory := &inventoryManager{
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+	}
+	limits := &purchaseLimiter{
+		orderRepo:    orderRepo,
+		holdRepo:     holdRepo,
+		waitlistRepo: waitlistRepo,
+		userRepo:     userRepo,
+		defaultLimit: maxTicketsPerUser,
+	}
+	return &holdService{
+		holdRepo:      holdRepo,
+		orderRepo:     orderRepo,
+		eventRepo:     eventRepo,
+		promoCodeRepo: promoCodeRepo,
+		eventSeatRepo: eventSeatRepo,
+		inventory:     inventory,
+		waitlist:      newWaitlistOfferer(waitlistRepo, eventRepo, ticketTypeRepo, userRepo, outboxRepo, inventory, limits, waitlistOfferWindow),
+		limits:        limits,
+		holdWindow:    holdWindow,
+		extension:     extension,
+		paymentWindow: paymentWindow,
+	}
+}
+
+// CreateHold takes the chosen tickets and seats out of inventory exactly like
+// a booking does, but parks them in a hold instead of a PENDING order.
+func (s *holdService) CreateHold(userID, eventID uint, input *entity.CreateHoldInput) (*entity.Hold, error) {
+	// Step 1: Check event exists, price the requested tiers and fail fast when
+	// they are clearly sold out
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	booking := &entity.BookingInput{
+		Quantity: input.Quantity,
+		Items:    input.Items,
+		SeatIDs:  input.SeatIDs,
+	}
+	now := time.Now()
+	items, qty, total, seats, err := prepareBooking(s.eventSeatRepo, event, booking, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Start database transaction
+	db := s.holdRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 3: Enforce the per-user limit; held tickets count towards it
+	if err := s.limits.check(tx, userID, event, qty); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 4: Decrement tier and event inventory (within transaction)
+	if err := s.inventory.reserve(tx, eventID, items); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 5: Create the hold
+	hold := &entity.Hold{
+		UserID:      userID,
+		EventID:     eventID,
+		Quantity:    qty,
+		TotalAmount: total,
+		Status:      entity.HoldStatusActive,
+		ExpiresAt:   now.Add(s.holdWindow),
+		Items:       make([]entity.HoldItem, len(items)),
+	}
+	for i, item := range items {
+		hold.Items[i] = entity.HoldItem{
+			TicketTypeID:   item.TicketTypeID,
+			TicketTypeName: item.TicketTypeName,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			Subtotal:       item.Subtotal,
+		}
+	}
+
+	if err := s.holdRepo.Save(tx, hold); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 6: Hold the chosen seats; the guard catches seats taken by a
+	// booking or hold that committed first
+	if len(seats) > 0 {
+		if err := s.eventSeatRepo.Reserve(tx, eventID, seatIDsOf(seats), hold.ID); err != nil {
+			tx.Rollback()
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				return nil, ErrSeatUnavailable
+			}
+			return nil, err
+		}
+	}
+
+	// Step 7: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetHold(userID, hold.ID)
+}
+
+func (s *holdService) GetUserHolds(userID uint) ([]entity.Hold, error) {
+	holds, err := s.holdRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range holds {
+		setCountdown(&holds[i], now)
+	}
+	return holds, nil
+}
+
+func (s *holdService) GetHold(userID, holdID uint) (*entity.Hold, error) {
+	hold, err := s.holdRepo.FindByID(holdID)
+	if err != nil || hold.UserID != userID {
+		return nil, ErrHoldNotFound
+	}
+	setCountdown(hold, time.Now())
+	return hold, nil
+}
+
+// ExtendHold gives an active hold another extension period, once.
+func (s *holdService) ExtendHold(userID, holdID uint) (*entity.Hold, error) {
+	db := s.holdRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	hold, err := s.lockActiveHold(tx, userID, holdID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if hold.ExtendedAt != nil {
+		tx.Rollback()
+		return nil, ErrHoldAlreadyExtended
+	}
+
+	if err := s.holdRepo.Extend(tx, hold.ID, hold.ExpiresAt.Add(s.extension), time.Now()); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return nil, ErrHoldAlreadyExtended
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetHold(userID, holdID)
+}
+
+// ReleaseHold empties the cart, returning its tickets and seats right away.
+func (s *holdService) ReleaseHold(userID, holdID uint) error {
+	db := s.holdRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	hold, err := s.lockActiveHold(tx, userID, holdID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.holdRepo.TransitionStatus(tx, hold.ID, entity.HoldStatusActive, entity.HoldStatusReleased); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.releaseHold(tx, hold); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CheckoutHold turns an active hold into a PENDING order in one transaction.
+// The tickets and seats are already taken from inventory, and counted against
+// the per-user limit, so they move to the order as they are and the order
+// only needs to be paid within the payment window.
+func (s *holdService) CheckoutHold(userID, holdID uint, input *entity.CheckoutHoldInput) (*entity.Order, error) {
+	db := s.holdRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 1: Lock the hold so it cannot expire or be released meanwhile
+	hold, err := s.lockActiveHold(tx, userID, holdID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 2: Create the order at the held prices
+	now := time.Now()
+	expiresAt := now.Add(s.paymentWindow)
+	order := &entity.Order{
+		UserID:      userID,
+		EventID:     hold.EventID,
+		Quantity:    hold.Quantity,
+		TotalAmount: hold.TotalAmount,
+		Status:      entity.OrderStatusPending,
+		ExpiresAt:   &expiresAt,
+		Items:       heldOrderItems(hold),
+	}
+
+	var redemption *entity.PromoRedemption
+	if input.PromoCode != "" {
+		redemption, err = applyPromoCode(tx, s.promoCodeRepo, order, input.PromoCode, now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := s.orderRepo.Save(tx, order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 3: Move the seats over and close the hold
+	if err := s.eventSeatRepo.AssignHoldToOrder(tx, hold.ID, order.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.holdRepo.MarkConverted(tx, hold.ID, order.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 4: Count the promo code redemption against the code's limits
+	if redemption != nil {
+		redemption.OrderID = order.ID
+		if err := s.promoCodeRepo.Redeem(tx, redemption); err != nil {
+			tx.Rollback()
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				return nil, ErrPromoCodeExhausted
+			}
+			return nil, err
+		}
+	}
+
+	// Step 5: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.FindByID(order.ID)
+}
+
+// ExpireHolds gives back the tickets and seats of holds that ran out. Each
+// hold is expired in its own transaction; the guarded transition makes it
+// safe to run alongside checkouts, extensions and reapers on other instances.
+func (s *holdService) ExpireHolds() (int, error) {
+	holds, err := s.holdRepo.FindExpired(time.Now(), holdExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, hold := range holds {
+		if err := s.expireHold(hold); err != nil {
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				continue
+			}
+			log.Printf("[HoldService] Failed to expire hold %d: %v", hold.ID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (s *holdService) expireHold(hold entity.Hold) error {
+	db := s.holdRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.holdRepo.Expire(tx, hold.ID, time.Now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.releaseHold(tx, &hold); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockActiveHold loads the user's hold under a row lock and checks it can
+// still be used.
+func (s *holdService) lockActiveHold(tx *gorm.DB, userID, holdID uint) (*entity.Hold, error) {
+	hold, err := s.holdRepo.FindByIDForUpdate(tx, holdID)
+	if err != nil || hold.UserID != userID {
+		return nil, ErrHoldNotFound
+	}
+	if hold.Status != entity.HoldStatusActive {
+		return nil, ErrHoldNotActive
+	}
+	if time.Now().After(hold.ExpiresAt) {
+		return nil, ErrHoldExpired
+	}
+	return hold, nil
+}
+
+// releaseHold gives back what a hold was keeping: its tickets, which are
+// offered to the waitlist first, and its seats.
+func (s *holdService) releaseHold(tx *gorm.DB, hold *entity.Hold) error {
+	if err := s.inventory.releaseItems(tx, hold.EventID, heldOrderItems(hold)); err != nil {
+		return err
+	}
+
+	if err := s.eventSeatRepo.ReleaseByHoldID(tx, hold.ID); err != nil {
+		return err
+	}
+
+	for _, item := range hold.Items {
+		if _, err := s.waitlist.offer(tx, item.TicketTypeID, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// heldOrderItems turns the lines of a hold into order lines, keeping the
+// prices from when the hold was made.
+func heldOrderItems(hold *entity.Hold) []entity.OrderItem {
+	items := make([]entity.OrderItem, len(hold.Items))
+	for i, item := range hold.Items {
+		items[i] = entity.OrderItem{
+			TicketTypeID:   item.TicketTypeID,
+			TicketTypeName: item.TicketTypeName,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			Subtotal:       item.Subtotal,
+		}
+	}
+	return items
+}
+
+func setCountdown(hold *entity.Hold, now time.Time) {
+	if hold.Status != entity.HoldStatusActive {
+		return
+	}
+	if remaining := hold.ExpiresAt.Sub(now); remaining > 0 {
+		hold.SecondsRemaining = int(remaining.Round(time.Second) / time.Second)
+	}
+}
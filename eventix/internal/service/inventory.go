@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// inventoryManager keeps tier inventory and the event's overall capacity in
+// step. Every method runs inside the caller's transaction.
+type inventoryManager struct {
+	eventRepo      repository.EventRepository
+	ticketTypeRepo repository.TicketTypeRepository
+}
+
+// reserve takes the items' tickets from their tiers and from the event. Items
+// must be sorted by tier ID, as buildOrderItems returns them, so concurrent
+// multi-tier bookings lock rows in the same sequence and cannot deadlock.
+func (m *inventoryManager) reserve(tx *gorm.DB, eventID uint, items []entity.OrderItem) error {
+	total := 0
+	for _, item := range items {
+		if err := m.ticketTypeRepo.DecrementAvailable(tx, item.TicketTypeID, item.Quantity); err != nil {
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				return fmt.Errorf("%w: %s", ErrInsufficientTickets, item.TicketTypeName)
+			}
+			return err
+		}
+		total += item.Quantity
+	}
+
+	if err := m.eventRepo.DecrementAvailableTickets(tx, eventID, total); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrInsufficientTickets
+		}
+		return err
+	}
+	return nil
+}
+
+// release returns an unpaid order's tickets. The order's items must be loaded.
+func (m *inventoryManager) release(tx *gorm.DB, order *entity.Order) error {
+	return m.releaseItems(tx, order.EventID, order.Items)
+}
+
+func (m *inventoryManager) releaseItems(tx *gorm.DB, eventID uint, items []entity.OrderItem) error {
+	total := 0
+	for _, item := range items {
+		if err := m.ticketTypeRepo.IncrementAvailable(tx, item.TicketTypeID, item.Quantity); err != nil {
+			return err
+		}
+		total += item.Quantity
+	}
+	return m.eventRepo.IncrementAvailableTickets(tx, eventID, total)
+}
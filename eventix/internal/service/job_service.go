@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/worker"
+)
+
+var (
+	ErrJobNotFound = errors.New("dead-letter job not found")
+)
+
+// maxDeadLetterPageSize caps page_size so a single request cannot load the
+// whole dead-letter table, payloads included.
+const maxDeadLetterPageSize = 100
+
+// JobService exposes the background job queue to admins.
+type JobService interface {
+	GetStats() (*entity.OutboxStats, error)
+	ListDeadLetters(page, pageSize int) ([]entity.OutboxMessage, int64, error)
+	RequeueDeadLetter(id uint) error
+	// PurgeProcessedJobs deletes messages processed longer ago than the retention
+	PurgeProcessedJobs() (int64, error)
+}
+
+type jobService struct {
+	outboxRepo repository.OutboxRepository
+	dispatcher *worker.OutboxDispatcher
+	retention  time.Duration
+}
+
+func NewJobService(outboxRepo repository.OutboxRepository, dispatcher *worker.OutboxDispatcher, retention time.Duration) JobService {
+	return &jobService{
+		outboxRepo: outboxRepo,
+		dispatcher: dispatcher,
+		retention:  retention,
+	}
+}
+
+func (s *jobService) GetStats() (*entity.OutboxStats, error) {
+	counts, err := s.outboxRepo.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := s.dispatcher.Metrics()
+	return &entity.OutboxStats{
+		Pending:      counts[entity.OutboxStatusPending],
+		Processing:   counts[entity.OutboxStatusProcessing],
+		Dead:         counts[entity.OutboxStatusDead],
+		Workers:      s.dispatcher.Workers(),
+		Processed:    metrics.Processed,
+		Failed:       metrics.Failed,
+		Retried:      metrics.Retried,
+		DeadLettered: metrics.DeadLettered,
+	}, nil
+}
+
+func (s *jobService) ListDeadLetters(page, pageSize int) ([]entity.OutboxMessage, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxDeadLetterPageSize {
+		pageSize = maxDeadLetterPageSize
+	}
+	return s.outboxRepo.FindDead(pageSize, (page-1)*pageSize)
+}
+
+func (s *jobService) RequeueDeadLetter(id uint) error {
+	if err := s.outboxRepo.Requeue(id); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrJobNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *jobService) PurgeProcessedJobs() (int64, error) {
+	return s.outboxRepo.PurgeDone(time.Now().Add(-s.retention))
+}
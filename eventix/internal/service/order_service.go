@@ -1,15 +1,23 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"sync"
+	"log"
+	"math"
+	"sort"
+	"time"
 
 	"eventix/internal/entity"
 	"eventix/internal/repository"
+	"eventix/pkg/payment"
+	"eventix/pkg/utils"
 	"eventix/pkg/worker"
+
+	"gorm.io/gorm"
 )
 
 var (
@@ -18,53 +26,131 @@ var (
 	ErrOrderAlreadyPaid    = errors.New("order already paid")
 	ErrOrderCancelled      = errors.New("order has been cancelled")
 	ErrUnauthorized        = errors.New("unauthorized to access this order")
+	ErrOrderExpired        = errors.New("order payment window has expired")
+	ErrOrderNotPending     = errors.New("order is no longer pending")
+
+	ErrInvalidBooking          = errors.New("booking must contain at least one ticket")
+	ErrTooManyTickets          = errors.New("too many tickets in one order")
+	ErrTicketTypeRequired      = errors.New("event has several ticket types, choose one per item")
+	ErrTicketTypeNotFound      = errors.New("ticket type not found for this event")
+	ErrTicketTypeNotOnSale     = errors.New("ticket type is not on sale")
+	ErrTicketTypeLimitExceeded = errors.New("ticket type per-order limit exceeded")
+
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	ErrPaymentDeclined          = errors.New("payment declined")
+	ErrPaymentTimeout           = errors.New("payment provider timed out")
+	ErrPaymentFailed            = errors.New("payment failed")
+	ErrPaymentInProgress        = errors.New("a payment for this order is awaiting confirmation")
+	ErrUnknownPaymentProvider   = errors.New("unknown payment provider")
+	ErrDuplicateWebhook         = errors.New("webhook event already processed")
+)
+
+const (
+	// expiryBatchSize caps how many orders a single reaper pass cancels.
+	expiryBatchSize = 100
+	// maxTicketsPerOrder caps the total quantity across all items of an order.
+	maxTicketsPerOrder = 10
 )
 
 type OrderService interface {
-	BookTickets(userID uint, eventID uint, qty int) (*entity.Order, error)
-	ProcessPayment(userID uint, orderID uint) (*entity.Order, error)
+	BookTickets(userID uint, eventID uint, input *entity.BookingInput) (*entity.Order, error)
+	ProcessPayment(userID uint, orderID uint, paymentMethod string) (*entity.Order, error)
 	CancelOrder(userID uint, orderID uint) error
 	GetUserOrders(userID uint) ([]entity.Order, error)
 	GetOrderByID(userID uint, orderID uint) (*entity.Order, error)
+	GetAnyOrderByID(orderID uint) (*entity.Order, error)
+	ListAllOrders(filter entity.OrderFilter) ([]entity.Order, int64, error)
+	ClaimWaitlistOffer(userID uint, entryID uint) (*entity.Order, error)
+	ExpirePendingOrders() (int, error)
+	// EnsureOrderExpiry gives pending orders from before payment windows existed an expiry
+	EnsureOrderExpiry() error
+	HandlePaymentWebhook(provider string, event *payment.WebhookEvent, payload []byte) error
 }
 
 type orderService struct {
-	orderRepo    repository.OrderRepository
-	eventRepo    repository.EventRepository
-	ticketRepo   repository.TicketRepository
-	emailChan    chan<- worker.EmailJob
-	bookingMutex sync.Mutex
+	orderRepo      repository.OrderRepository
+	eventRepo      repository.EventRepository
+	ticketRepo     repository.TicketRepository
+	promoCodeRepo  repository.PromoCodeRepository
+	waitlistRepo   repository.WaitlistRepository
+	eventSeatRepo  repository.EventSeatRepository
+	ticketTypeRepo repository.TicketTypeRepository
+	userRepo       repository.UserRepository
+	paymentRepo    repository.PaymentRepository
+	outboxRepo     repository.OutboxRepository
+	inventory      *inventoryManager
+	waitlist       *waitlistOfferer
+	limits         *purchaseLimiter
+	providers      *payment.Registry
+	paymentWindow  time.Duration
+	paymentTimeout time.Duration
 }
 
 func NewOrderService(
 	orderRepo repository.OrderRepository,
 	eventRepo repository.EventRepository,
 	ticketRepo repository.TicketRepository,
-	emailChan chan<- worker.EmailJob,
+	ticketTypeRepo repository.TicketTypeRepository,
+	promoCodeRepo repository.PromoCodeRepository,
+	waitlistRepo repository.WaitlistRepository,
+	eventSeatRepo repository.EventSeatRepository,
+	holdRepo repository.HoldRepository,
+	userRepo repository.UserRepository,
+	paymentRepo repository.PaymentRepository,
+	outboxRepo repository.OutboxRepository,
+	providers *payment.Registry,
+	paymentWindow time.Duration,
+	paymentTimeout time.Duration,
+	waitlistOfferWindow time.Duration,
+	maxTicketsPerUser int,
 ) OrderService {
+	inventory := &inventoryManager{
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+	}
+	limits := &purchaseLimiter{
+		orderRepo:    orderRepo,
+		holdRepo:     holdRepo,
+		waitlistRepo: waitlistRepo,
+		userRepo:     userRepo,
+		defaultLimit: maxTicketsPerUser,
+	}
 	return &orderService{
-		orderRepo:  orderRepo,
-		eventRepo:  eventRepo,
-		ticketRepo: ticketRepo,
-		emailChan:  emailChan,
+		orderRepo:      orderRepo,
+		eventRepo:      eventRepo,
+		ticketRepo:     ticketRepo,
+		promoCodeRepo:  promoCodeRepo,
+		waitlistRepo:   waitlistRepo,
+		eventSeatRepo:  eventSeatRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		userRepo:       userRepo,
+		paymentRepo:    paymentRepo,
+		outboxRepo:     outboxRepo,
+		inventory:      inventory,
+		waitlist:       newWaitlistOfferer(waitlistRepo, eventRepo, ticketTypeRepo, userRepo, outboxRepo, inventory, limits, waitlistOfferWindow),
+		limits:         limits,
+		providers:      providers,
+		paymentWindow:  paymentWindow,
+		paymentTimeout: paymentTimeout,
 	}
 }
 
-// BookTickets handles ticket booking with mutex lock and database transaction
-// to prevent overselling when multiple users book simultaneously
-func (s *orderService) BookTickets(userID uint, eventID uint, qty int) (*entity.Order, error) {
-	// CRITICAL SECTION: Lock to prevent race conditions
-	s.bookingMutex.Lock()
-	defer s.bookingMutex.Unlock()
-
-	// Step 1: Check event exists and has enough tickets
+// BookTickets reserves tickets and creates a PENDING order in one transaction.
+// Overselling is prevented by the guarded decrements in the database rather
+// than an in-process lock, so bookings stay correct across multiple instances
+// and bookings for different events never wait on each other. Chosen seats are
+// held for the order the same way.
+func (s *orderService) BookTickets(userID uint, eventID uint, input *entity.BookingInput) (*entity.Order, error) {
+	// Step 1: Check event exists, price the requested tiers and fail fast when
+	// they are clearly sold out
 	event, err := s.eventRepo.FindByID(eventID)
 	if err != nil {
 		return nil, ErrEventNotFound
 	}
 
-	if event.AvailableTickets < qty {
-		return nil, ErrInsufficientTickets
+	items, qty, total, seats, err := prepareBooking(s.eventSeatRepo, event, input, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	// Step 2: Start database transaction
@@ -80,26 +166,39 @@ func (s *orderService) BookTickets(userID uint, eventID uint, qty int) (*entity.
 		}
 	}()
 
-	// Step 3: Decrement available tickets (within transaction)
-	if err := s.eventRepo.DecrementAvailableTickets(tx, eventID, qty); err != nil {
+	// Step 3: Enforce the per-user limit across the user's orders and holds
+	if err := s.limits.check(tx, userID, event, qty); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// Double-check the affected rows to ensure tickets were actually decremented
-	var updatedEvent entity.Event
-	if err := tx.First(&updatedEvent, eventID).Error; err != nil {
+	// Step 4: Decrement tier and event inventory (within transaction); a
+	// failed guard means another booking took the remaining tickets first
+	if err := s.inventory.reserve(tx, eventID, items); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// Step 4: Create order record
+	// Step 5: Create order record, reserving the tickets until the payment window closes
+	now := time.Now()
+	expiresAt := now.Add(s.paymentWindow)
 	order := &entity.Order{
 		UserID:      userID,
 		EventID:     eventID,
 		Quantity:    qty,
-		TotalAmount: event.Price * float64(qty),
+		TotalAmount: total,
 		Status:      entity.OrderStatusPending,
+		ExpiresAt:   &expiresAt,
+		Items:       items,
+	}
+
+	var redemption *entity.PromoRedemption
+	if input.PromoCode != "" {
+		redemption, err = applyPromoCode(tx, s.promoCodeRepo, order, input.PromoCode, now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	if err := s.orderRepo.Save(tx, order); err != nil {
@@ -107,7 +206,32 @@ func (s *orderService) BookTickets(userID uint, eventID uint, qty int) (*entity.
 		return nil, err
 	}
 
-	// Step 5: Commit transaction
+	// Step 6: Hold the chosen seats. The event row is locked by the inventory
+	// decrement, so bookings of one event get here one at a time; the guard
+	// still catches seats taken by an order that committed first
+	if len(seats) > 0 {
+		if err := s.eventSeatRepo.Hold(tx, eventID, seatIDsOf(seats), order.ID); err != nil {
+			tx.Rollback()
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				return nil, ErrSeatUnavailable
+			}
+			return nil, err
+		}
+	}
+
+	// Step 7: Count the promo code redemption against the code's limits
+	if redemption != nil {
+		redemption.OrderID = order.ID
+		if err := s.promoCodeRepo.Redeem(tx, redemption); err != nil {
+			tx.Rollback()
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				return nil, ErrPromoCodeExhausted
+			}
+			return nil, err
+		}
+	}
+
+	// Step 8: Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -116,8 +240,12 @@ func (s *orderService) BookTickets(userID uint, eventID uint, qty int) (*entity.
 	return order, nil
 }
 
-// ProcessPayment handles payment processing, ticket generation, and async email notification
-func (s *orderService) ProcessPayment(userID uint, orderID uint) (*entity.Order, error) {
+// ProcessPayment charges the order through the provider registered for the
+// payment method, then generates tickets and queues the email notification.
+// Every attempt is recorded as a Payment, including declines and timeouts.
+// Provider calls happen outside the order transaction; if the order can no
+// longer be completed afterwards, the money is released again.
+func (s *orderService) ProcessPayment(userID uint, orderID uint, paymentMethod string) (*entity.Order, error) {
 	// Step 1: Get and validate order
 	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
@@ -136,10 +264,82 @@ func (s *orderService) ProcessPayment(userID uint, orderID uint) (*entity.Order,
 		return nil, ErrOrderCancelled
 	}
 
-	// Step 2: Start transaction for payment processing
+	if order.Status == entity.OrderStatusExpired || isPastPaymentWindow(order) {
+		return nil, ErrOrderExpired
+	}
+
+	// Failed and refunded orders cannot be paid; charging them would only
+	// have to be refunded once the status transition fails
+	if order.Status != entity.OrderStatusPending {
+		return nil, ErrOrderNotPending
+	}
+
+	// An attempt still waiting for its webhook may yet capture the money, so a
+	// second charge must not be started alongside it
+	for _, attempt := range order.Payments {
+		if attempt.Status == entity.PaymentStatusPending && attempt.ProviderRef != "" {
+			return nil, ErrPaymentInProgress
+		}
+	}
+
+	provider, err := s.providers.Get(paymentMethod)
+	if err != nil {
+		return nil, ErrUnsupportedPaymentMethod
+	}
+
+	// Step 2: Record the attempt before talking to the provider
+	attempt := &entity.Payment{
+		OrderID:       orderID,
+		Provider:      provider.Name(),
+		PaymentMethod: paymentMethod,
+		Amount:        order.TotalAmount,
+		Status:        entity.PaymentStatusPending,
+	}
+	if err := s.paymentRepo.Save(nil, attempt); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.paymentTimeout)
+	defer cancel()
+
+	// Step 3: Authorize the charge
+	auth, err := provider.Authorize(ctx, payment.AuthorizeRequest{
+		OrderID:       orderID,
+		Amount:        order.TotalAmount,
+		PaymentMethod: paymentMethod,
+	})
+	if err != nil {
+		return nil, s.failPayment(attempt, err)
+	}
+
+	attempt.ProviderRef = auth.Reference
+	attempt.Status = entity.PaymentStatusAuthorized
+	if auth.Pending {
+		// The gateway confirms through the payment webhook; the order stays
+		// PENDING until then
+		attempt.Status = entity.PaymentStatusPending
+	}
+	if err := s.paymentRepo.Update(nil, attempt); err != nil {
+		return nil, err
+	}
+
+	if auth.Pending {
+		return s.orderRepo.FindByID(orderID)
+	}
+
+	// Step 4: Capture the funds before touching the order, so no row lock is
+	// held across provider round-trips; a failed capture releases the hold
+	if err := provider.Capture(ctx, attempt.ProviderRef, attempt.Amount); err != nil {
+		mapped := s.failPayment(attempt, err)
+		s.voidAuthorization(provider, attempt)
+		return nil, mapped
+	}
+
+	// Step 5: Start transaction for payment processing
 	db := s.orderRepo.GetDB()
 	tx := db.Begin()
 	if tx.Error != nil {
+		s.releaseAuthorization(provider, attempt, "failed to start transaction")
 		return nil, tx.Error
 	}
 
@@ -149,62 +349,364 @@ func (s *orderService) ProcessPayment(userID uint, orderID uint) (*entity.Order,
 		}
 	}()
 
-	// Step 3: Update order status to PAID
-	if err := s.orderRepo.UpdateStatus(tx, orderID, entity.OrderStatusPaid); err != nil {
+	// Step 6: Update order status to PAID, guarded so a concurrent cancel or
+	// the expiry reaper cannot be overwritten; if one won, pay the money back
+	if err := s.orderRepo.TransitionStatus(tx, orderID, entity.OrderStatusPending, entity.OrderStatusPaid); err != nil {
 		tx.Rollback()
+		s.releaseAuthorization(provider, attempt, "order is no longer pending")
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return nil, ErrOrderNotPending
+		}
 		return nil, err
 	}
 
-	// Step 4: Generate tickets
-	tickets := make([]entity.Ticket, order.Quantity)
-	for i := 0; i < order.Quantity; i++ {
-		ticketCode, err := generateTicketCode()
-		if err != nil {
+	// Step 7: Generate tickets and queue the confirmation email in the outbox
+	if err := s.fulfillOrder(tx, order); err != nil {
+		tx.Rollback()
+		s.releaseAuthorization(provider, attempt, "failed to issue tickets")
+		return nil, err
+	}
+
+	attempt.Status = entity.PaymentStatusCaptured
+	if err := s.paymentRepo.Update(tx, attempt); err != nil {
+		tx.Rollback()
+		s.releaseAuthorization(provider, attempt, "failed to record capture")
+		return nil, err
+	}
+
+	// Step 8: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		s.releaseAuthorization(provider, attempt, "failed to commit order")
+		return nil, err
+	}
+
+	// Step 9: Fetch updated order with tickets
+	return s.orderRepo.FindByID(orderID)
+}
+
+// HandlePaymentWebhook applies an asynchronous payment result. The signature
+// must already have been verified by the caller. Deliveries are de-duplicated
+// by provider event ID, and events older than the last one applied to the
+// payment are recorded but otherwise ignored so out-of-order deliveries cannot
+// move a payment backwards.
+func (s *orderService) HandlePaymentWebhook(providerName string, event *payment.WebhookEvent, payload []byte) error {
+	if _, err := s.providers.GetByName(providerName); err != nil {
+		return ErrUnknownPaymentProvider
+	}
+
+	db := s.orderRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
 			tx.Rollback()
-			return nil, err
 		}
+	}()
 
-		tickets[i] = entity.Ticket{
-			OrderID:    orderID,
-			EventID:    order.EventID,
-			TicketCode: ticketCode,
-			Status:     entity.TicketStatusValid,
+	// Step 1: Record the delivery; a conflict means it is a replay
+	if err := s.paymentRepo.SaveWebhookEvent(tx, &entity.PaymentWebhookEvent{
+		Provider:        providerName,
+		ProviderEventID: event.ID,
+		Type:            event.Type,
+		OrderID:         event.OrderID,
+		Payload:         string(payload),
+	}); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrDuplicateWebhook
 		}
+		return err
 	}
 
-	if err := s.ticketRepo.SaveBatch(tx, tickets); err != nil {
+	// Step 2: Lock the order so webhooks, payments and cancellations serialize
+	order, err := s.orderRepo.FindByIDForUpdate(tx, event.OrderID)
+	if err != nil {
 		tx.Rollback()
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+
+	// Step 3: Find the attempt this event refers to
+	attempt, err := s.paymentRepo.FindByProviderRef(tx, providerName, event.Reference)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			tx.Rollback()
+			return err
+		}
+		attempt = &entity.Payment{
+			OrderID:       order.ID,
+			Provider:      providerName,
+			PaymentMethod: "webhook",
+			ProviderRef:   event.Reference,
+			Amount:        event.Amount,
+			Status:        entity.PaymentStatusPending,
+		}
+		if err := s.paymentRepo.Save(tx, attempt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if attempt.OrderID != order.ID {
+		tx.Rollback()
+		return ErrPaymentFailed
+	}
+
+	if attempt.LastEventAt != nil && event.OccurredAt.Before(*attempt.LastEventAt) {
+		log.Printf("[OrderService] Ignoring stale %s for payment %d", event.Type, attempt.ID)
+		return tx.Commit().Error
+	}
+
+	// Step 4: Apply the outcome
+	var refundReason string
+	switch event.Type {
+	case payment.WebhookPaymentSucceeded:
+		alreadyCaptured := attempt.Status == entity.PaymentStatusCaptured
+		attempt.Status = entity.PaymentStatusCaptured
+		if !sameAmount(event.Amount, order.TotalAmount) {
+			// A partial or mismatched capture must not fulfil the order; pay
+			// back what was captured and leave the order as it is
+			log.Printf("[OrderService] Payment %d captured %.2f for order %d totalling %.2f, refunding",
+				attempt.ID, event.Amount, order.ID, order.TotalAmount)
+			attempt.Amount = event.Amount
+			refundReason = fmt.Sprintf("captured amount %.2f does not match order total %.2f", event.Amount, order.TotalAmount)
+			break
+		}
+		switch order.Status {
+		case entity.OrderStatusPending:
+			if err := s.orderRepo.TransitionStatus(tx, order.ID, entity.OrderStatusPending, entity.OrderStatusPaid); err != nil {
+				tx.Rollback()
+				return err
+			}
+			if err := s.fulfillOrder(tx, order); err != nil {
+				tx.Rollback()
+				return err
+			}
+		case entity.OrderStatusPaid:
+			// A repeat for the attempt that paid the order changes nothing;
+			// money captured by any other attempt is a double charge
+			if !alreadyCaptured {
+				refundReason = "order was already paid by another payment"
+			}
+		default:
+			// The order was cancelled, expired or failed before the money
+			// arrived; it cannot be fulfilled any more, so pay it back
+			refundReason = "order could not be fulfilled"
+		}
+
+	case payment.WebhookPaymentFailed:
+		if attempt.Status == entity.PaymentStatusCaptured {
+			log.Printf("[OrderService] Ignoring %s for captured payment %d", event.Type, attempt.ID)
+			return tx.Commit().Error
+		}
+		attempt.Status = entity.PaymentStatusFailed
+		attempt.FailureReason = event.FailureReason
+		if order.Status == entity.OrderStatusPending {
+			if err := s.orderRepo.TransitionStatus(tx, order.ID, entity.OrderStatusPending, entity.OrderStatusFailed); err != nil {
+				tx.Rollback()
+				return err
+			}
+			if err := s.releaseOrder(tx, order); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+	default:
+		log.Printf("[OrderService] Ignoring unknown webhook event type %q", event.Type)
+		return tx.Commit().Error
 	}
 
-	// Step 5: Commit transaction
+	occurredAt := event.OccurredAt
+	attempt.LastEventAt = &occurredAt
+	if err := s.paymentRepo.Update(tx, attempt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Step 5: Commit, then run side effects that must not hold the lock
 	if err := tx.Commit().Error; err != nil {
-		return nil, err
+		return err
 	}
 
-	// Step 6: Fetch updated order with tickets
-	updatedOrder, err := s.orderRepo.FindByID(orderID)
+	if refundReason != "" {
+		s.refundUnfulfillable(attempt, refundReason)
+	}
+
+	return nil
+}
+
+// refundUnfulfillable returns a captured payment that cannot fulfil its order.
+func (s *orderService) refundUnfulfillable(attempt *entity.Payment, reason string) {
+	provider, err := s.providers.GetByName(attempt.Provider)
 	if err != nil {
-		return nil, err
+		log.Printf("[OrderService] Cannot refund payment %d: %v", attempt.ID, err)
+		return
+	}
+	s.releaseAuthorization(provider, attempt, reason)
+}
+
+// sameAmount compares two money amounts to the cent.
+func sameAmount(a, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
+
+// failPayment records a failed provider call on the attempt and maps it to the
+// error returned to the client.
+func (s *orderService) failPayment(attempt *entity.Payment, cause error) error {
+	mapped := ErrPaymentFailed
+	attempt.Status = entity.PaymentStatusFailed
+	switch {
+	case errors.Is(cause, payment.ErrDeclined):
+		mapped = ErrPaymentDeclined
+		attempt.Status = entity.PaymentStatusDeclined
+	case errors.Is(cause, context.DeadlineExceeded):
+		mapped = ErrPaymentTimeout
+	}
+	attempt.FailureReason = cause.Error()
+
+	if err := s.paymentRepo.Update(nil, attempt); err != nil {
+		log.Printf("[OrderService] Failed to record payment %d failure: %v", attempt.ID, err)
+	}
+	return mapped
+}
+
+// releaseAuthorization gives the money back when the order could not be
+// completed after the provider already accepted the charge.
+func (s *orderService) releaseAuthorization(provider payment.PaymentProvider, attempt *entity.Payment, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.paymentTimeout)
+	defer cancel()
+
+	attempt.Status = entity.PaymentStatusRefunded
+	if err := provider.Refund(ctx, attempt.ProviderRef, attempt.Amount, paymentRefundKey(attempt.ID)); err != nil {
+		log.Printf("[OrderService] Failed to release payment %d (%s): %v", attempt.ID, attempt.ProviderRef, err)
+		attempt.Status = entity.PaymentStatusFailed
+	}
+	attempt.FailureReason = reason
+
+	if err := s.paymentRepo.Update(nil, attempt); err != nil {
+		log.Printf("[OrderService] Failed to record payment %d release: %v", attempt.ID, err)
+	}
+}
+
+// voidAuthorization releases the hold on the buyer's funds after a failed
+// capture, leaving the attempt's recorded failure as it is.
+func (s *orderService) voidAuthorization(provider payment.PaymentProvider, attempt *entity.Payment) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.paymentTimeout)
+	defer cancel()
+
+	if err := provider.Refund(ctx, attempt.ProviderRef, attempt.Amount, paymentRefundKey(attempt.ID)); err != nil {
+		log.Printf("[OrderService] Failed to void authorization of payment %d (%s): %v", attempt.ID, attempt.ProviderRef, err)
+	}
+}
+
+// paymentRefundKey is the idempotency key of giving back a whole payment
+// attempt, shared by releases and voids so the attempt is repaid only once.
+func paymentRefundKey(paymentID uint) string {
+	return fmt.Sprintf("payment-%d", paymentID)
+}
+
+// fulfillOrder generates one ticket per booked seat of the order, tagged with
+// its tier, and writes the confirmation email to the outbox, all within tx so
+// the email is sent if and only if the payment commits. Tickets are saved with
+// a placeholder code first because the signed code embeds the ticket ID.
+func (s *orderService) fulfillOrder(tx *gorm.DB, order *entity.Order) error {
+	// Seated tickets are issued for the seats the order holds
+	heldSeats, err := s.eventSeatRepo.FindByOrderID(tx, order.ID)
+	if err != nil {
+		return err
 	}
+	seatsByTier := make(map[uint][]entity.EventSeat)
+	for _, seat := range heldSeats {
+		seatsByTier[seat.TicketTypeID] = append(seatsByTier[seat.TicketTypeID], seat)
+	}
+
+	tickets := make([]entity.Ticket, 0, order.Quantity)
+	var seats []*entity.Seat
+	for _, item := range order.Items {
+		ticketTypeID := item.TicketTypeID
+		tierSeats := seatsByTier[ticketTypeID]
+		for i := 0; i < item.Quantity; i++ {
+			ticketCode, err := generateTicketCode()
+			if err != nil {
+				return err
+			}
 
-	// Step 7: Send async email notification (fire and forget)
-	// This runs in background via goroutine worker
-	go func() {
-		s.emailChan <- worker.EmailJob{
-			Order: *updatedOrder,
-			Email: fmt.Sprintf("user_%d@example.com", userID),
+			ticket := entity.Ticket{
+				OrderID:      order.ID,
+				HolderID:     &order.UserID,
+				EventID:      order.EventID,
+				TicketTypeID: &ticketTypeID,
+				TicketCode:   ticketCode,
+				Status:       entity.TicketStatusValid,
+			}
+			var seat *entity.Seat
+			if i < len(tierSeats) {
+				ticket.SeatID = &tierSeats[i].SeatID
+				seat = &tierSeats[i].Seat
+			}
+			tickets = append(tickets, ticket)
+			seats = append(seats, seat)
 		}
-	}()
+	}
+
+	if err := s.ticketRepo.SaveBatch(tx, tickets); err != nil {
+		return err
+	}
 
-	return updatedOrder, nil
+	if len(heldSeats) > 0 {
+		if err := s.eventSeatRepo.MarkSold(tx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for i := range tickets {
+		ticketCode, err := signTicketCode(&tickets[i], now)
+		if err != nil {
+			return err
+		}
+		if err := s.ticketRepo.UpdateCode(tx, tickets[i].ID, ticketCode); err != nil {
+			return err
+		}
+		tickets[i].TicketCode = ticketCode
+		tickets[i].Seat = seats[i]
+	}
+
+	// Snapshot the paid order for the email so the worker needs no lookups
+	confirmed := *order
+	confirmed.Status = entity.OrderStatusPaid
+	confirmed.Tickets = tickets
+	confirmed.Payments = nil
+	if confirmed.Event.ID == 0 {
+		event, err := s.eventRepo.FindByID(order.EventID)
+		if err != nil {
+			return err
+		}
+		confirmed.Event = *event
+	}
+
+	buyer, err := s.userRepo.FindByID(order.UserID)
+	if err != nil {
+		return err
+	}
+
+	return s.outboxRepo.Enqueue(tx, entity.OutboxTopicOrderConfirmation, worker.EmailJob{
+		Order: confirmed,
+		Email: buyer.Email,
+		Name:  buyer.Name,
+	})
 }
 
-// CancelOrder cancels a pending order and restores available tickets
+// CancelOrder cancels a pending order and restores available tickets. The
+// guarded status transition ensures tickets are returned at most once even if
+// the order is paid, cancelled or expired concurrently.
 func (s *orderService) CancelOrder(userID uint, orderID uint) error {
-	s.bookingMutex.Lock()
-	defer s.bookingMutex.Unlock()
-
 	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
 		return ErrOrderNotFound
@@ -215,13 +717,17 @@ func (s *orderService) CancelOrder(userID uint, orderID uint) error {
 	}
 
 	if order.Status == entity.OrderStatusPaid {
-		return errors.New("cannot cancel a paid order")
+		return errors.New("cannot cancel a paid order, request a refund instead")
 	}
 
 	if order.Status == entity.OrderStatusCancelled {
 		return errors.New("order already cancelled")
 	}
 
+	if order.Status == entity.OrderStatusExpired {
+		return ErrOrderExpired
+	}
+
 	db := s.orderRepo.GetDB()
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -235,13 +741,16 @@ func (s *orderService) CancelOrder(userID uint, orderID uint) error {
 	}()
 
 	// Update order status
-	if err := s.orderRepo.UpdateStatus(tx, orderID, entity.OrderStatusCancelled); err != nil {
+	if err := s.orderRepo.TransitionStatus(tx, orderID, entity.OrderStatusPending, entity.OrderStatusCancelled); err != nil {
 		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrOrderNotPending
+		}
 		return err
 	}
 
-	// Restore available tickets
-	if err := s.eventRepo.IncrementAvailableTickets(tx, order.EventID, order.Quantity); err != nil {
+	// Restore available tickets and the promo code redemption
+	if err := s.releaseOrder(tx, order); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -249,8 +758,213 @@ func (s *orderService) CancelOrder(userID uint, orderID uint) error {
 	return tx.Commit().Error
 }
 
+// EnsureOrderExpiry backfills expires_at on PENDING orders that predate the
+// payment window; otherwise they would hold their tickets forever.
+func (s *orderService) EnsureOrderExpiry() error {
+	updated, err := s.orderRepo.BackfillExpiry(s.paymentWindow)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		log.Printf("[Orders] Set payment deadlines on %d pending order(s)", updated)
+	}
+	return nil
+}
+
+// ExpirePendingOrders cancels PENDING orders whose payment window has passed
+// and returns their tickets to the event. Each order is expired in its own
+// transaction; the guarded status transition makes it safe to run alongside
+// payments, manual cancellations and reapers on other instances.
+func (s *orderService) ExpirePendingOrders() (int, error) {
+	orders, err := s.orderRepo.FindExpiredPending(time.Now(), expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, order := range orders {
+		if err := s.expireOrder(order); err != nil {
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				continue
+			}
+			log.Printf("[OrderService] Failed to expire order %d: %v", order.ID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (s *orderService) expireOrder(order entity.Order) error {
+	db := s.orderRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.orderRepo.TransitionStatus(tx, order.ID, entity.OrderStatusPending, entity.OrderStatusExpired); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.releaseOrder(tx, &order); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// releaseOrder gives back what an unpaid order was holding: its tickets, which
+// are offered to the waitlist first, its seats and its promo code redemption.
+func (s *orderService) releaseOrder(tx *gorm.DB, order *entity.Order) error {
+	if err := s.inventory.release(tx, order); err != nil {
+		return err
+	}
+
+	if err := s.eventSeatRepo.ReleaseByOrderID(tx, order.ID); err != nil {
+		return err
+	}
+
+	for _, item := range order.Items {
+		if _, err := s.waitlist.offer(tx, item.TicketTypeID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if order.PromoCodeID == nil {
+		return nil
+	}
+	return s.promoCodeRepo.ReleaseByOrderID(tx, order.ID, time.Now())
+}
+
+// ClaimWaitlistOffer turns the tickets held for a waitlist offer into a
+// PENDING order at the tier's current price. The tickets are already
+// reserved, so the order only needs to be paid within the payment window.
+func (s *orderService) ClaimWaitlistOffer(userID uint, entryID uint) (*entity.Order, error) {
+	db := s.orderRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 1: Lock the entry so the offer cannot expire while it is claimed
+	entry, err := s.waitlistRepo.FindByIDForUpdate(tx, entryID)
+	if err != nil || entry.UserID != userID {
+		tx.Rollback()
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	if entry.Status != entity.WaitlistStatusOffered {
+		tx.Rollback()
+		return nil, ErrNoWaitlistOffer
+	}
+
+	now := time.Now()
+	if entry.OfferExpiresAt != nil && now.After(*entry.OfferExpiresAt) {
+		tx.Rollback()
+		return nil, ErrWaitlistOfferExpired
+	}
+
+	tier, err := s.ticketTypeRepo.FindByID(entry.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, ErrTicketTypeNotFound
+	}
+
+	event, err := s.eventRepo.FindByID(entry.EventID)
+	if err != nil {
+		tx.Rollback()
+		return nil, ErrEventNotFound
+	}
+
+	// Sales may have closed since the offer was made; the held tickets are
+	// given back instead of being sold
+	if !salesOpen(event, tier, now) {
+		if err := s.closeOffer(tx, entry); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTicketTypeNotOnSale, tier.Name)
+	}
+
+	if err := s.limits.checkClaim(tx, userID, event, entry); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 2: Create the order for the held tickets
+	expiresAt := now.Add(s.paymentWindow)
+	subtotal := tier.Price * float64(entry.Quantity)
+	order := &entity.Order{
+		UserID:      userID,
+		EventID:     entry.EventID,
+		Quantity:    entry.Quantity,
+		TotalAmount: subtotal,
+		Status:      entity.OrderStatusPending,
+		ExpiresAt:   &expiresAt,
+		Items: []entity.OrderItem{{
+			TicketTypeID:   tier.ID,
+			TicketTypeName: tier.Name,
+			Quantity:       entry.Quantity,
+			UnitPrice:      tier.Price,
+			Subtotal:       subtotal,
+		}},
+	}
+
+	if err := s.orderRepo.Save(tx, order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 3: Close the offer
+	if err := s.waitlistRepo.MarkClaimed(tx, entry.ID, order.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 4: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.FindByID(order.ID)
+}
+
+// closeOffer expires an offer that can no longer be claimed and returns the
+// tickets it held to the tier.
+func (s *orderService) closeOffer(tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	if err := s.waitlistRepo.TransitionStatus(tx, entry.ID, entity.WaitlistStatusOffered, entity.WaitlistStatusExpired); err != nil {
+		return err
+	}
+	return s.inventory.releaseItems(tx, entry.EventID, heldItems(entry))
+}
+
 func (s *orderService) GetUserOrders(userID uint) ([]entity.Order, error) {
-	return s.orderRepo.FindByUserID(userID)
+	orders, err := s.orderRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		hideTransferredCodes(&orders[i])
+	}
+	return orders, nil
 }
 
 func (s *orderService) GetOrderByID(userID uint, orderID uint) (*entity.Order, error) {
@@ -263,9 +977,160 @@ func (s *orderService) GetOrderByID(userID uint, orderID uint) (*entity.Order, e
 		return nil, ErrUnauthorized
 	}
 
+	hideTransferredCodes(order)
 	return order, nil
 }
 
+// GetAnyOrderByID skips the ownership check; callers must hold orders:read_all.
+func (s *orderService) GetAnyOrderByID(orderID uint) (*entity.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *orderService) ListAllOrders(filter entity.OrderFilter) ([]entity.Order, int64, error) {
+	return s.orderRepo.FindAll(filter)
+}
+
+// prepareBooking validates a booking request against the event and prices it,
+// failing fast when the tickets or seats are clearly gone. Chosen seats are
+// turned into tier lines and returned so the caller can hold them.
+func prepareBooking(eventSeatRepo repository.EventSeatRepository, event *entity.Event, input *entity.BookingInput, now time.Time) ([]entity.OrderItem, int, float64, []entity.EventSeat, error) {
+	var seats []entity.EventSeat
+	if len(input.SeatIDs) > 0 {
+		if input.Quantity > 0 || len(input.Items) > 0 {
+			return nil, 0, 0, nil, ErrSeatsWithQuantity
+		}
+		var err error
+		seats, err = findBookableSeats(eventSeatRepo, event.ID, input.SeatIDs)
+		if err != nil {
+			return nil, 0, 0, nil, err
+		}
+		seatInput := *input
+		seatInput.Items = seatBookingItems(seats)
+		input = &seatInput
+	}
+
+	items, qty, total, err := buildOrderItems(event, input, now)
+	if err != nil {
+		return nil, 0, 0, nil, err
+	}
+
+	if err := checkSeatSelection(event, items, seats); err != nil {
+		return nil, 0, 0, nil, err
+	}
+
+	if event.AvailableTickets < qty {
+		return nil, 0, 0, nil, ErrInsufficientTickets
+	}
+	return items, qty, total, seats, nil
+}
+
+// salesOpen reports whether tickets of tier can be sold at now: its sales
+// window is open and the event has not taken place yet.
+func salesOpen(event *entity.Event, tier *entity.TicketType, now time.Time) bool {
+	return tier.OnSale(now) && now.Before(event.Date)
+}
+
+// buildOrderItems validates the requested lines against the event's tiers and
+// returns them priced, sorted by tier, together with the total quantity and
+// amount. A bare quantity is accepted for events with a single tier.
+func buildOrderItems(event *entity.Event, input *entity.BookingInput, now time.Time) ([]entity.OrderItem, int, float64, error) {
+	lines := input.Items
+	if len(lines) == 0 {
+		if input.Quantity <= 0 {
+			return nil, 0, 0, ErrInvalidBooking
+		}
+		if len(event.TicketTypes) != 1 {
+			return nil, 0, 0, ErrTicketTypeRequired
+		}
+		lines = []entity.BookingItem{{TicketTypeID: event.TicketTypes[0].ID, Quantity: input.Quantity}}
+	}
+
+	// Merge repeated tiers so per-order limits cannot be bypassed
+	requested := make(map[uint]int)
+	for _, line := range lines {
+		requested[line.TicketTypeID] += line.Quantity
+	}
+
+	tiers := make(map[uint]*entity.TicketType, len(event.TicketTypes))
+	for i := range event.TicketTypes {
+		tiers[event.TicketTypes[i].ID] = &event.TicketTypes[i]
+	}
+
+	items := make([]entity.OrderItem, 0, len(requested))
+	qty := 0
+	total := 0.0
+	for ticketTypeID, quantity := range requested {
+		tier, ok := tiers[ticketTypeID]
+		if !ok {
+			return nil, 0, 0, ErrTicketTypeNotFound
+		}
+		if !salesOpen(event, tier, now) {
+			return nil, 0, 0, fmt.Errorf("%w: %s", ErrTicketTypeNotOnSale, tier.Name)
+		}
+		if tier.MaxPerOrder > 0 && quantity > tier.MaxPerOrder {
+			return nil, 0, 0, fmt.Errorf("%w: at most %d %s per order", ErrTicketTypeLimitExceeded, tier.MaxPerOrder, tier.Name)
+		}
+		if tier.AvailableQuantity < quantity {
+			return nil, 0, 0, fmt.Errorf("%w: %s", ErrInsufficientTickets, tier.Name)
+		}
+
+		subtotal := tier.Price * float64(quantity)
+		items = append(items, entity.OrderItem{
+			TicketTypeID:   tier.ID,
+			TicketTypeName: tier.Name,
+			Quantity:       quantity,
+			UnitPrice:      tier.Price,
+			Subtotal:       subtotal,
+		})
+		qty += quantity
+		total += subtotal
+	}
+
+	if qty > maxTicketsPerOrder {
+		return nil, 0, 0, ErrTooManyTickets
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].TicketTypeID < items[j].TicketTypeID
+	})
+
+	return items, qty, total, nil
+}
+
+// hideTransferredCodes blanks the codes of tickets the buyer has given away,
+// so only the current holder can present them.
+func hideTransferredCodes(order *entity.Order) {
+	for i := range order.Tickets {
+		if !heldByBuyer(order, &order.Tickets[i]) {
+			order.Tickets[i].TicketCode = ""
+		}
+	}
+}
+
+func isPastPaymentWindow(order *entity.Order) bool {
+	return order.ExpiresAt != nil && time.Now().After(*order.ExpiresAt)
+}
+
+// signTicketCode issues the code scanners can verify offline for the ticket
+// as currently held.
+func signTicketCode(ticket *entity.Ticket, issuedAt time.Time) (string, error) {
+	claims := utils.TicketClaims{
+		TicketID: ticket.ID,
+		EventID:  ticket.EventID,
+		IssuedAt: issuedAt,
+	}
+	if ticket.HolderID != nil {
+		claims.HolderID = *ticket.HolderID
+	}
+	return utils.SignTicketCode(claims)
+}
+
+// generateTicketCode returns a random placeholder code for tickets that have
+// not been saved yet.
 func generateTicketCode() (string, error) {
 	bytes := make([]byte, 16)
 	if _, err := rand.Read(bytes); err != nil {
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPromoCodeNotFound    = errors.New("promo code not found")
+	ErrPromoCodeTaken       = errors.New("promo code already exists")
+	ErrPromoCodeInUse       = errors.New("promo code has been redeemed, deactivate it instead")
+	ErrInvalidDiscount      = errors.New("percentage discount cannot exceed 100")
+	ErrInvalidPromoWindow   = errors.New("valid_until must be after valid_from")
+	ErrPromoCodeInvalid     = errors.New("promo code is invalid or inactive")
+	ErrPromoCodeNotValidNow = errors.New("promo code is not valid at this time")
+	ErrPromoCodeWrongEvent  = errors.New("promo code does not apply to this event")
+	ErrPromoCodeMinQuantity = errors.New("order does not meet the promo code's minimum quantity")
+	ErrPromoCodeExhausted   = errors.New("promo code has reached its redemption limit")
+	ErrPromoCodeUserLimit   = errors.New("you have already used this promo code the maximum number of times")
+)
+
+// PromoCodeService lets administrators manage discount codes. Codes are
+// redeemed by OrderService during booking.
+type PromoCodeService interface {
+	ListPromoCodes(filter entity.PromoCodeFilter) ([]entity.PromoCode, int64, error)
+	GetPromoCode(id uint) (*entity.PromoCode, error)
+	CreatePromoCode(input *entity.CreatePromoCodeInput) (*entity.PromoCode, error)
+	UpdatePromoCode(id uint, input *entity.UpdatePromoCodeInput) (*entity.PromoCode, error)
+	DeletePromoCode(id uint) error
+}
+
+type promoCodeService struct {
+	promoCodeRepo repository.PromoCodeRepository
+	eventRepo     repository.EventRepository
+}
+
+func NewPromoCodeService(promoCodeRepo repository.PromoCodeRepository, eventRepo repository.EventRepository) PromoCodeService {
+	return &promoCodeService{
+		promoCodeRepo: promoCodeRepo,
+		eventRepo:     eventRepo,
+	}
+}
+
+func (s *promoCodeService) ListPromoCodes(filter entity.PromoCodeFilter) ([]entity.PromoCode, int64, error) {
+	return s.promoCodeRepo.FindAll(filter)
+}
+
+func (s *promoCodeService) GetPromoCode(id uint) (*entity.PromoCode, error) {
+	promo, err := s.promoCodeRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromoCodeNotFound
+	}
+	return promo, nil
+}
+
+func (s *promoCodeService) CreatePromoCode(input *entity.CreatePromoCodeInput) (*entity.PromoCode, error) {
+	if input.DiscountType == entity.DiscountTypePercentage && input.DiscountValue > 100 {
+		return nil, ErrInvalidDiscount
+	}
+	if !validPromoWindow(input.ValidFrom, input.ValidUntil) {
+		return nil, ErrInvalidPromoWindow
+	}
+
+	if input.EventID != nil {
+		if _, err := s.eventRepo.FindByID(*input.EventID); err != nil {
+			return nil, ErrEventNotFound
+		}
+	}
+
+	if _, err := s.promoCodeRepo.FindByCode(input.Code); err == nil {
+		return nil, ErrPromoCodeTaken
+	}
+
+	promo := &entity.PromoCode{
+		Code:           strings.ToUpper(input.Code),
+		Description:    input.Description,
+		DiscountType:   input.DiscountType,
+		DiscountValue:  input.DiscountValue,
+		EventID:        input.EventID,
+		ValidFrom:      input.ValidFrom,
+		ValidUntil:     input.ValidUntil,
+		MaxRedemptions: input.MaxRedemptions,
+		MaxPerUser:     input.MaxPerUser,
+		MinQuantity:    input.MinQuantity,
+		Active:         true,
+	}
+
+	if err := s.promoCodeRepo.Save(promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+func (s *promoCodeService) UpdatePromoCode(id uint, input *entity.UpdatePromoCodeInput) (*entity.PromoCode, error) {
+	promo, err := s.promoCodeRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromoCodeNotFound
+	}
+
+	if input.Description != nil {
+		promo.Description = *input.Description
+	}
+	if input.ValidFrom != nil {
+		promo.ValidFrom = input.ValidFrom
+	}
+	if input.ValidUntil != nil {
+		promo.ValidUntil = input.ValidUntil
+	}
+	if input.MaxRedemptions != nil {
+		promo.MaxRedemptions = *input.MaxRedemptions
+	}
+	if input.MaxPerUser != nil {
+		promo.MaxPerUser = *input.MaxPerUser
+	}
+	if input.MinQuantity != nil {
+		promo.MinQuantity = *input.MinQuantity
+	}
+	if input.Active != nil {
+		promo.Active = *input.Active
+	}
+
+	if !validPromoWindow(promo.ValidFrom, promo.ValidUntil) {
+		return nil, ErrInvalidPromoWindow
+	}
+
+	if err := s.promoCodeRepo.Update(promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// DeletePromoCode removes a code that was never redeemed. Codes with history
+// are kept for the orders that reference them and can only be deactivated.
+func (s *promoCodeService) DeletePromoCode(id uint) error {
+	if _, err := s.promoCodeRepo.FindByID(id); err != nil {
+		return ErrPromoCodeNotFound
+	}
+
+	redemptions, err := s.promoCodeRepo.CountRedemptions(id)
+	if err != nil {
+		return err
+	}
+	if redemptions > 0 {
+		return ErrPromoCodeInUse
+	}
+
+	return s.promoCodeRepo.Delete(id)
+}
+
+// applyPromoCode validates code against an unsaved order and takes its
+// discount off the total. It returns the redemption to record once the order
+// has an ID. The code's row stays locked until tx ends, so its limits hold
+// under concurrent bookings.
+func applyPromoCode(tx *gorm.DB, repo repository.PromoCodeRepository, order *entity.Order, code string, now time.Time) (*entity.PromoRedemption, error) {
+	promo, err := repo.FindByCodeForUpdate(tx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromoCodeInvalid
+		}
+		return nil, err
+	}
+
+	if !promo.Active {
+		return nil, ErrPromoCodeInvalid
+	}
+	if !promo.Valid(now) {
+		return nil, ErrPromoCodeNotValidNow
+	}
+	if promo.EventID != nil && *promo.EventID != order.EventID {
+		return nil, ErrPromoCodeWrongEvent
+	}
+	if order.Quantity < promo.MinQuantity {
+		return nil, fmt.Errorf("%w: at least %d tickets required", ErrPromoCodeMinQuantity, promo.MinQuantity)
+	}
+	if promo.MaxRedemptions > 0 && promo.RedemptionCount >= promo.MaxRedemptions {
+		return nil, ErrPromoCodeExhausted
+	}
+
+	if promo.MaxPerUser > 0 {
+		used, err := repo.CountActiveRedemptionsByUser(tx, promo.ID, order.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(promo.MaxPerUser) {
+			return nil, ErrPromoCodeUserLimit
+		}
+	}
+
+	discount := promo.Discount(order.TotalAmount)
+	order.PromoCodeID = &promo.ID
+	order.PromoCode = promo.Code
+	order.DiscountAmount = discount
+	order.TotalAmount -= discount
+
+	return &entity.PromoRedemption{
+		PromoCodeID: promo.ID,
+		UserID:      order.UserID,
+		Discount:    discount,
+		Status:      entity.RedemptionStatusActive,
+	}, nil
+}
+
+func validPromoWindow(from, until *time.Time) bool {
+	return from == nil || until == nil || until.After(*from)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrPurchaseLimitExceeded = errors.New("per-user ticket limit for this event exceeded")
+
+// PurchaseLimitError is returned when a user asks for more tickets than they
+// may still buy for an event.
+type PurchaseLimitError struct {
+	Limit     int
+	Remaining int
+}
+
+func (e *PurchaseLimitError) Error() string {
+	return fmt.Sprintf("%s: at most %d per user, %d left for you", ErrPurchaseLimitExceeded, e.Limit, e.Remaining)
+}
+
+func (e *PurchaseLimitError) Unwrap() error {
+	return ErrPurchaseLimitExceeded
+}
+
+// purchaseLimiter caps how many tickets one user can get for an event across
+// all of their orders, holds and waitlist offers. Every method runs inside the
+// caller's transaction.
+type purchaseLimiter struct {
+	orderRepo    repository.OrderRepository
+	holdRepo     repository.HoldRepository
+	waitlistRepo repository.WaitlistRepository
+	userRepo     repository.UserRepository
+	defaultLimit int
+}
+
+// limit returns the event's per-user limit; 0 means unlimited.
+func (l *purchaseLimiter) limit(event *entity.Event) int {
+	if event.MaxTicketsPerUser != nil {
+		return *event.MaxTicketsPerUser
+	}
+	return l.defaultLimit
+}
+
+// check fails when the user cannot get qty more tickets for the event. The
+// user's row stays locked until tx ends, so concurrent bookings of the same
+// user are counted one after the other and cannot overshoot the limit
+// together. Tickets of PENDING and PAID orders, of unexpired holds and of open
+// waitlist offers count.
+func (l *purchaseLimiter) check(tx *gorm.DB, userID uint, event *entity.Event, qty int) error {
+	limit := l.limit(event)
+	if limit == 0 {
+		return nil
+	}
+
+	if _, err := l.userRepo.FindByIDForUpdate(tx, userID); err != nil {
+		return err
+	}
+	return l.checkCount(tx, userID, event, qty)
+}
+
+// checkClaim is check for claiming a waitlist offer, whose tickets already
+// count towards the limit while the offer is open.
+func (l *purchaseLimiter) checkClaim(tx *gorm.DB, userID uint, event *entity.Event, entry *entity.WaitlistEntry) error {
+	limit := l.limit(event)
+	if limit == 0 {
+		return nil
+	}
+
+	if _, err := l.userRepo.FindByIDForUpdate(tx, userID); err != nil {
+		return err
+	}
+	used, err := l.count(tx, userID, event.ID)
+	if err != nil {
+		return err
+	}
+	if used <= limit {
+		return nil
+	}
+
+	remaining := limit - (used - entry.Quantity)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &PurchaseLimitError{Limit: limit, Remaining: remaining}
+}
+
+// precheck is check without the lock, for failing fast outside a booking.
+func (l *purchaseLimiter) precheck(userID uint, event *entity.Event, qty int) error {
+	if l.limit(event) == 0 {
+		return nil
+	}
+	return l.checkCount(nil, userID, event, qty)
+}
+
+// hasRoom reports whether the user can get qty more tickets, without taking
+// the lock. Waitlist offers use it to pass over users who could not claim
+// them; the claim checks again under the lock.
+func (l *purchaseLimiter) hasRoom(tx *gorm.DB, userID uint, event *entity.Event, qty int) (bool, error) {
+	if l.limit(event) == 0 {
+		return true, nil
+	}
+	err := l.checkCount(tx, userID, event, qty)
+	if errors.Is(err, ErrPurchaseLimitExceeded) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *purchaseLimiter) checkCount(tx *gorm.DB, userID uint, event *entity.Event, qty int) error {
+	limit := l.limit(event)
+
+	used, err := l.count(tx, userID, event.ID)
+	if err != nil {
+		return err
+	}
+
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	if qty > remaining {
+		return &PurchaseLimitError{Limit: limit, Remaining: remaining}
+	}
+	return nil
+}
+
+// count returns how many tickets the user has or is about to get for the event.
+func (l *purchaseLimiter) count(tx *gorm.DB, userID, eventID uint) (int, error) {
+	ordered, err := l.orderRepo.CountTicketsByUser(tx, userID, eventID)
+	if err != nil {
+		return 0, err
+	}
+	held, err := l.holdRepo.CountActiveTicketsByUser(tx, userID, eventID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	offered, err := l.waitlistRepo.CountOfferedTicketsByUser(tx, userID, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return int(ordered + held + offered), nil
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/payment"
+	"eventix/pkg/worker"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotRefundable  = errors.New("only paid orders can be refunded")
+	ErrNothingToRefund     = errors.New("order has no tickets left to refund")
+	ErrTicketNotInOrder    = errors.New("ticket does not belong to this order")
+	ErrTicketNotRefundable = errors.New("ticket has already been used, refunded or transferred")
+	ErrRefundNotAllowed    = errors.New("refund policy does not allow a refund this close to the event")
+)
+
+// RefundTier pays back Percent of the ticket price when the refund is
+// requested at least DaysBefore days before the event starts.
+type RefundTier struct {
+	DaysBefore int
+	Percent    int
+}
+
+// RefundPolicy is a list of tiers sorted by DaysBefore, longest first.
+type RefundPolicy []RefundTier
+
+// ParseRefundPolicy reads a policy such as "7:100,2:50": a full refund until
+// seven days before the event, half until two days before, nothing after.
+// "0:0" disables refunds; a policy without tiers is rejected so a blank value
+// cannot disable them by accident.
+func ParseRefundPolicy(spec string) (RefundPolicy, error) {
+	var policy RefundPolicy
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		days, percent, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid refund policy tier %q, expected days:percent", part)
+		}
+		d, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid days in refund policy tier %q", part)
+		}
+		p, err := strconv.Atoi(strings.TrimSpace(percent))
+		if err != nil || p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid percent in refund policy tier %q", part)
+		}
+		policy = append(policy, RefundTier{DaysBefore: d, Percent: p})
+	}
+
+	if len(policy) == 0 {
+		return nil, errors.New("refund policy has no tiers, use 0:0 to disable refunds")
+	}
+
+	sort.Slice(policy, func(i, j int) bool {
+		return policy[i].DaysBefore > policy[j].DaysBefore
+	})
+	return policy, nil
+}
+
+// Percent returns the share of the price refunded at now for an event on
+// eventDate.
+func (p RefundPolicy) Percent(eventDate, now time.Time) int {
+	for _, tier := range p {
+		if !now.After(eventDate.AddDate(0, 0, -tier.DaysBefore)) {
+			return tier.Percent
+		}
+	}
+	return 0
+}
+
+type RefundService interface {
+	RefundOrder(actor entity.Actor, orderID uint, input *entity.RefundInput) (*entity.Refund, error)
+	// CompleteRefund pays out a pending refund; it is run by the outbox
+	CompleteRefund(refundID uint) error
+}
+
+type refundService struct {
+	orderRepo      repository.OrderRepository
+	ticketRepo     repository.TicketRepository
+	refundRepo     repository.RefundRepository
+	paymentRepo    repository.PaymentRepository
+	eventSeatRepo  repository.EventSeatRepository
+	outboxRepo     repository.OutboxRepository
+	inventory      *inventoryManager
+	waitlist       *waitlistOfferer
+	providers      *payment.Registry
+	policy         RefundPolicy
+	paymentTimeout time.Duration
+}
+
+func NewRefundService(
+	orderRepo repository.OrderRepository,
+	eventRepo repository.EventRepository,
+	ticketRepo repository.TicketRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	refundRepo repository.RefundRepository,
+	paymentRepo repository.PaymentRepository,
+	waitlistRepo repository.WaitlistRepository,
+	eventSeatRepo repository.EventSeatRepository,
+	holdRepo repository.HoldRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	providers *payment.Registry,
+	policy RefundPolicy,
+	paymentTimeout time.Duration,
+	waitlistOfferWindow time.Duration,
+	maxTicketsPerUser int,
+) RefundService {
+	inventory := &inventoryManager{
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+	}
+	limits := &purchaseLimiter{
+		orderRepo:    orderRepo,
+		holdRepo:     holdRepo,
+		waitlistRepo: waitlistRepo,
+		userRepo:     userRepo,
+		defaultLimit: maxTicketsPerUser,
+	}
+	return &refundService{
+		orderRepo:      orderRepo,
+		ticketRepo:     ticketRepo,
+		refundRepo:     refundRepo,
+		paymentRepo:    paymentRepo,
+		eventSeatRepo:  eventSeatRepo,
+		outboxRepo:     outboxRepo,
+		inventory:      inventory,
+		waitlist:       newWaitlistOfferer(waitlistRepo, eventRepo, ticketTypeRepo, userRepo, outboxRepo, inventory, limits, waitlistOfferWindow),
+		providers:      providers,
+		policy:         policy,
+		paymentTimeout: paymentTimeout,
+	}
+}
+
+// RefundOrder pays back some or all unused tickets of a paid order. Buyers are
+// refunded according to the policy; holders of orders:refund may refund any
+// order and override the policy. The refunded tickets are invalidated and
+// their inventory is returned, going to the waitlist first. The money is paid
+// out through the outbox once all of that has committed, so a failed commit
+// never leaves a refund paid for tickets that are still valid.
+func (s *refundService) RefundOrder(actor entity.Actor, orderID uint, input *entity.RefundInput) (*entity.Refund, error) {
+	staff := actor.Can(entity.PermissionOrdersRefund)
+	if input.OverridePolicy && !staff {
+		return nil, ErrUnauthorized
+	}
+
+	db := s.orderRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 1: Lock the order so concurrent refunds of it serialize
+	if _, err := s.orderRepo.FindByIDForUpdate(tx, orderID); err != nil {
+		tx.Rollback()
+		return nil, ErrOrderNotFound
+	}
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		tx.Rollback()
+		return nil, ErrOrderNotFound
+	}
+
+	if order.UserID != actor.UserID && !staff {
+		tx.Rollback()
+		return nil, ErrUnauthorized
+	}
+
+	if order.Status != entity.OrderStatusPaid && order.Status != entity.OrderStatusPartiallyRefunded {
+		tx.Rollback()
+		return nil, ErrOrderNotRefundable
+	}
+
+	captured := capturedPayment(order.Payments)
+	if captured == nil {
+		tx.Rollback()
+		return nil, ErrOrderNotRefundable
+	}
+
+	// Step 2: Work out which tickets are refunded and how much they are worth
+	tickets, err := selectRefundTickets(order, input.TicketIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	percent := 100
+	if !input.OverridePolicy {
+		percent = s.policy.Percent(order.Event.Date, time.Now())
+		if percent == 0 {
+			tx.Rollback()
+			return nil, ErrRefundNotAllowed
+		}
+	}
+
+	amount := refundAmount(order, tickets, percent)
+
+	refund := &entity.Refund{
+		OrderID:       order.ID,
+		PaymentID:     captured.ID,
+		RequestedByID: actor.UserID,
+		TicketCount:   len(tickets),
+		Percent:       percent,
+		Amount:        amount,
+		Reason:        input.Reason,
+		Status:        entity.RefundStatusSucceeded,
+	}
+	if amount > 0 {
+		refund.Status = entity.RefundStatusPending
+	}
+	if err := s.refundRepo.Save(tx, refund); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 3: Invalidate the tickets; a guard failure means one was scanned
+	ticketIDs := make([]uint, len(tickets))
+	for i, ticket := range tickets {
+		ticketIDs[i] = ticket.ID
+	}
+	if err := s.ticketRepo.MarkRefunded(tx, ticketIDs, refund.ID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return nil, ErrTicketNotRefundable
+		}
+		return nil, err
+	}
+
+	// Step 4: Return the inventory and seats, and offer them to the waitlist
+	items := refundedItems(tickets)
+	if err := s.inventory.releaseItems(tx, order.EventID, items); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if seatIDs := refundedSeats(tickets); len(seatIDs) > 0 {
+		if err := s.eventSeatRepo.Release(tx, order.EventID, seatIDs); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	for _, item := range items {
+		if _, err := s.waitlist.offer(tx, item.TicketTypeID, time.Now()); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Step 5: Update the order
+	status := entity.OrderStatusPartiallyRefunded
+	if len(tickets) == countValid(order.Tickets) && !hasUsedTickets(order.Tickets) {
+		status = entity.OrderStatusRefunded
+	}
+	if err := s.orderRepo.ApplyRefund(tx, order.ID, order.Status, status, amount); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if order.RefundedAmount+amount >= captured.Amount {
+		captured.Status = entity.PaymentStatusRefunded
+		if err := s.paymentRepo.Update(tx, captured); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Step 6: Queue the payout, which runs once this transaction commits
+	if refund.Status == entity.RefundStatusPending {
+		if err := s.outboxRepo.Enqueue(tx, entity.OutboxTopicRefund, worker.RefundJob{RefundID: refund.ID}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Step 7: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// refundPayment asks the provider to pay the refund back under a key derived
+// from its ID, so repeating the call for the same refund never pays twice.
+func (s *refundService) refundPayment(refund *entity.Refund, captured *entity.Payment) error {
+	provider, err := s.providers.GetByName(captured.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.paymentTimeout)
+	defer cancel()
+	return provider.Refund(ctx, captured.ProviderRef, refund.Amount, fmt.Sprintf("refund-%d", refund.ID))
+}
+
+// CompleteRefund pays a pending refund back to the payment it was taken from.
+// The refund is moved to PROCESSING before the provider is called. A delivery
+// that finds it already processing cannot know whether the provider paid, e.g.
+// after a timeout or a crash, so it reconciles by repeating the refund under
+// the same idempotency key, which the provider answers without paying again.
+// Completed refunds are skipped.
+func (s *refundService) CompleteRefund(refundID uint) error {
+	refund, err := s.refundRepo.FindByID(refundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[RefundService] Refund %d no longer exists, skipping payout", refundID)
+			return nil
+		}
+		return err
+	}
+
+	switch refund.Status {
+	case entity.RefundStatusPending:
+		if err := s.refundRepo.MarkProcessing(refund.ID); err != nil {
+			if errors.Is(err, repository.ErrNoRowsAffected) {
+				// Another delivery claimed it first and pays it out
+				return nil
+			}
+			return err
+		}
+	case entity.RefundStatusProcessing:
+		log.Printf("[RefundService] Refund %d was already being paid out, reconciling with the provider", refund.ID)
+	default:
+		return nil
+	}
+
+	captured, err := s.paymentRepo.FindByID(refund.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.refundPayment(refund, captured); err != nil {
+		if recordErr := s.refundRepo.RecordFailure(refund.ID, err.Error()); recordErr != nil {
+			log.Printf("[RefundService] Failed to record payout failure of refund %d: %v", refund.ID, recordErr)
+		}
+		return err
+	}
+
+	if err := s.refundRepo.MarkSucceeded(refund.ID); err != nil && !errors.Is(err, repository.ErrNoRowsAffected) {
+		return err
+	}
+	log.Printf("[RefundService] Refund %d of %.2f for order %d paid out", refund.ID, refund.Amount, refund.OrderID)
+	return nil
+}
+
+func capturedPayment(payments []entity.Payment) *entity.Payment {
+	for i := range payments {
+		if payments[i].Status == entity.PaymentStatusCaptured {
+			return &payments[i]
+		}
+	}
+	return nil
+}
+
+// selectRefundTickets returns the requested tickets, or every refundable
+// ticket when none are requested. Only valid tickets still held by the buyer
+// can be refunded; a transferred ticket belongs to someone else now.
+func selectRefundTickets(order *entity.Order, requested []uint) ([]entity.Ticket, error) {
+	refundable := func(ticket entity.Ticket) bool {
+		return ticket.Status == entity.TicketStatusValid && (ticket.HolderID == nil || *ticket.HolderID == order.UserID)
+	}
+
+	if len(requested) == 0 {
+		var valid []entity.Ticket
+		for _, ticket := range order.Tickets {
+			if refundable(ticket) {
+				valid = append(valid, ticket)
+			}
+		}
+		if len(valid) == 0 {
+			return nil, ErrNothingToRefund
+		}
+		return valid, nil
+	}
+
+	byID := make(map[uint]entity.Ticket, len(order.Tickets))
+	for _, ticket := range order.Tickets {
+		byID[ticket.ID] = ticket
+	}
+
+	seen := make(map[uint]bool, len(requested))
+	selected := make([]entity.Ticket, 0, len(requested))
+	for _, id := range requested {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		ticket, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrTicketNotInOrder, id)
+		}
+		if !refundable(ticket) {
+			return nil, fmt.Errorf("%w: %d", ErrTicketNotRefundable, id)
+		}
+		selected = append(selected, ticket)
+	}
+	return selected, nil
+}
+
+// refundAmount prices the tickets at what was actually paid for them, i.e.
+// their tier's unit price less their share of any discount, and applies the
+// policy percentage. The result never exceeds what is left to refund.
+func refundAmount(order *entity.Order, tickets []entity.Ticket, percent int) float64 {
+	unitPrices := make(map[uint]float64, len(order.Items))
+	subtotal := 0.0
+	for _, item := range order.Items {
+		unitPrices[item.TicketTypeID] = item.UnitPrice
+		subtotal += item.Subtotal
+	}
+
+	gross := 0.0
+	for _, ticket := range tickets {
+		if ticket.TicketTypeID != nil {
+			gross += unitPrices[*ticket.TicketTypeID]
+		}
+	}
+
+	paidShare := 1.0
+	if subtotal > 0 {
+		paidShare = order.TotalAmount / subtotal
+	}
+
+	amount := math.Round(gross*paidShare*float64(percent)) / 100
+	if remaining := order.TotalAmount - order.RefundedAmount; amount > remaining {
+		amount = math.Max(remaining, 0)
+	}
+	return amount
+}
+
+// refundedItems groups refunded tickets by tier in the shape inventoryManager
+// expects, sorted by tier like booking does.
+func refundedItems(tickets []entity.Ticket) []entity.OrderItem {
+	quantities := make(map[uint]int)
+	for _, ticket := range tickets {
+		if ticket.TicketTypeID != nil {
+			quantities[*ticket.TicketTypeID]++
+		}
+	}
+
+	items := make([]entity.OrderItem, 0, len(quantities))
+	for ticketTypeID, quantity := range quantities {
+		items = append(items, entity.OrderItem{TicketTypeID: ticketTypeID, Quantity: quantity})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].TicketTypeID < items[j].TicketTypeID
+	})
+	return items
+}
+
+func refundedSeats(tickets []entity.Ticket) []uint {
+	var seatIDs []uint
+	for _, ticket := range tickets {
+		if ticket.SeatID != nil {
+			seatIDs = append(seatIDs, *ticket.SeatID)
+		}
+	}
+	return seatIDs
+}
+
+func countValid(tickets []entity.Ticket) int {
+	count := 0
+	for _, ticket := range tickets {
+		if ticket.Status == entity.TicketStatusValid {
+			count++
+		}
+	}
+	return count
+}
+
+func hasUsedTickets(tickets []entity.Ticket) bool {
+	for _, ticket := range tickets {
+		if ticket.Status == entity.TicketStatusUsed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRoleAlreadyAssigned = errors.New("user already has this role")
+	ErrRoleNotAssigned     = errors.New("user does not have this role")
+	ErrCannotRevokeOwnRole = errors.New("cannot revoke your own role")
+	ErrLastRole            = errors.New("user must keep at least one role")
+
+	ErrPermissionNotFound   = errors.New("permission not found")
+	ErrPermissionNotGranted = errors.New("role does not have this permission")
+	ErrAdminRoleFixed       = errors.New("admin always holds every permission")
+)
+
+// RoleService manages roles, their permissions and who holds them.
+type RoleService interface {
+	// EnsureDefaults seeds built-in permissions and roles and migrates legacy user roles
+	EnsureDefaults() error
+	ListRoles() ([]entity.Role, error)
+	ListPermissions() ([]entity.Permission, error)
+	GetUserRoles(userID uint) ([]entity.Role, error)
+	AssignRole(userID uint, roleName string) error
+	RevokeRole(actorID, userID uint, roleName string) error
+	GrantPermission(roleName, permissionName string) error
+	RevokePermission(roleName, permissionName string) error
+}
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+// EnsureDefaults is safe to run on every startup.
+// Flow:
+// 1. Insert missing permissions
+// 2. Create missing built-in roles with their default permissions
+// 3. Give admin every permission, including ones added since the last start
+// 4. Convert the legacy users.role column into role assignments
+func (s *roleService) EnsureDefaults() error {
+	// Step 1: Permissions
+	if err := s.roleRepo.EnsurePermissions(entity.DefaultPermissions); err != nil {
+		return err
+	}
+
+	// Step 2 & 3: Roles; permissions of existing roles other than admin are
+	// left as configured
+	allPermissions := make([]string, 0, len(entity.DefaultPermissions))
+	for _, permission := range entity.DefaultPermissions {
+		allPermissions = append(allPermissions, permission.Name)
+	}
+
+	for _, def := range entity.DefaultRoles {
+		role := def
+		created, err := s.roleRepo.EnsureRole(&role)
+		if err != nil {
+			return err
+		}
+
+		var grant []string
+		switch {
+		case role.Name == entity.RoleAdmin:
+			grant = allPermissions
+		case created:
+			grant = entity.DefaultRolePermissions[role.Name]
+		}
+		if err := s.roleRepo.AddPermissions(role.ID, grant); err != nil {
+			return err
+		}
+	}
+
+	// Step 4: Legacy role column
+	migrated, err := s.roleRepo.MigrateLegacyUserRoles(entity.RoleUser)
+	if err != nil {
+		return err
+	}
+	if migrated > 0 {
+		log.Printf("[Roles] Migrated %d legacy user role(s)", migrated)
+	}
+
+	return nil
+}
+
+func (s *roleService) ListRoles() ([]entity.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+func (s *roleService) ListPermissions() ([]entity.Permission, error) {
+	return s.roleRepo.FindAllPermissions()
+}
+
+func (s *roleService) GetUserRoles(userID uint) ([]entity.Role, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.roleRepo.FindByUserID(userID)
+}
+
+// AssignRole grants a role. The user picks up the new permissions the next
+// time they log in or refresh their access token.
+func (s *roleService) AssignRole(userID uint, roleName string) error {
+	role, user, err := s.findRoleAndUser(userID, roleName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.AssignToUser(nil, user.ID, role.ID); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrRoleAlreadyAssigned
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeRole removes a role. Users cannot revoke their own roles so an admin
+// cannot lock everyone out by accident, and a user's last role cannot be
+// revoked: users without any role are given one from the legacy role column on
+// startup, which would bring a revoked role back.
+func (s *roleService) RevokeRole(actorID, userID uint, roleName string) error {
+	if actorID == userID {
+		return ErrCannotRevokeOwnRole
+	}
+
+	role, user, err := s.findRoleAndUser(userID, roleName)
+	if err != nil {
+		return err
+	}
+
+	held, err := s.roleRepo.FindByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	if len(held) == 1 && held[0].ID == role.ID {
+		return ErrLastRole
+	}
+
+	if err := s.roleRepo.RemoveFromUser(user.ID, role.ID); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrRoleNotAssigned
+		}
+		return err
+	}
+	return nil
+}
+
+// GrantPermission adds a permission to a role; granting one the role already
+// holds is a no-op. Holders pick it up the next time they log in or refresh
+// their access token.
+func (s *roleService) GrantPermission(roleName, permissionName string) error {
+	role, permission, err := s.findRoleAndPermission(roleName, permissionName)
+	if err != nil {
+		return err
+	}
+	return s.roleRepo.AddPermissions(role.ID, []string{permission.Name})
+}
+
+// RevokePermission removes a permission from a role. Admin is re-granted every
+// permission on startup, so its permissions cannot be revoked.
+func (s *roleService) RevokePermission(roleName, permissionName string) error {
+	role, permission, err := s.findRoleAndPermission(roleName, permissionName)
+	if err != nil {
+		return err
+	}
+	if role.Name == entity.RoleAdmin {
+		return ErrAdminRoleFixed
+	}
+
+	if err := s.roleRepo.RemovePermission(role.ID, permission.ID); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrPermissionNotGranted
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *roleService) findRoleAndPermission(roleName, permissionName string) (*entity.Role, *entity.Permission, error) {
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrRoleNotFound
+		}
+		return nil, nil, err
+	}
+
+	permission, err := s.roleRepo.FindPermissionByName(permissionName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrPermissionNotFound
+		}
+		return nil, nil, err
+	}
+
+	return role, permission, nil
+}
+
+func (s *roleService) findRoleAndUser(userID uint, roleName string) (*entity.Role, *entity.User, error) {
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrRoleNotFound
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		return nil, nil, err
+	}
+
+	return role, user, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/ticketdoc"
+)
+
+var (
+	ErrNoPrintableTickets = errors.New("order has no tickets left to print")
+	ErrTicketTransferred  = errors.New("ticket has been transferred to someone else")
+)
+
+// TicketDocumentService renders the tickets of an order for its buyer. Tickets
+// that were refunded or transferred away are never rendered.
+type TicketDocumentService interface {
+	RenderTicketQR(userID, orderID, ticketID uint) ([]byte, error)
+	RenderOrderPDF(userID, orderID uint) ([]byte, error)
+}
+
+type ticketDocumentService struct {
+	orderRepo repository.OrderRepository
+	userRepo  repository.UserRepository
+}
+
+func NewTicketDocumentService(orderRepo repository.OrderRepository, userRepo repository.UserRepository) TicketDocumentService {
+	return &ticketDocumentService{
+		orderRepo: orderRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *ticketDocumentService) RenderTicketQR(userID, orderID, ticketID uint) ([]byte, error) {
+	order, err := s.ownedPaidOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ticket := range order.Tickets {
+		if ticket.ID != ticketID {
+			continue
+		}
+		if ticket.Status == entity.TicketStatusRefunded {
+			return nil, ErrTicketRefunded
+		}
+		if !heldByBuyer(order, &ticket) {
+			return nil, ErrTicketTransferred
+		}
+		return ticketdoc.QRCode(ticket.TicketCode)
+	}
+	return nil, ErrTicketNotInOrder
+}
+
+// RenderOrderPDF prints every ticket of the order the buyer can still use,
+// one per page.
+func (s *ticketDocumentService) RenderOrderPDF(userID, orderID uint) ([]byte, error) {
+	order, err := s.ownedPaidOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	buyer, err := s.userRepo.FindByID(order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	tierNames := make(map[uint]string, len(order.Items))
+	for _, item := range order.Items {
+		tierNames[item.TicketTypeID] = item.TicketTypeName
+	}
+
+	doc := ticketdoc.Order{
+		OrderID:       order.ID,
+		EventTitle:    order.Event.Title,
+		EventDate:     order.Event.Date,
+		EventLocation: order.Event.Location,
+	}
+	for i := range order.Tickets {
+		ticket := &order.Tickets[i]
+		if ticket.Status == entity.TicketStatusRefunded || !heldByBuyer(order, ticket) {
+			continue
+		}
+		printed := ticketdoc.Ticket{Code: ticket.TicketCode, HolderName: buyer.Name}
+		if ticket.TicketTypeID != nil {
+			printed.TierName = tierNames[*ticket.TicketTypeID]
+		}
+		if ticket.Seat != nil {
+			printed.Seat = ticket.Seat.Label
+		}
+		doc.Tickets = append(doc.Tickets, printed)
+	}
+	if len(doc.Tickets) == 0 {
+		return nil, ErrNoPrintableTickets
+	}
+
+	return ticketdoc.PDF(doc)
+}
+
+func (s *ticketDocumentService) ownedPaidOrder(userID, orderID uint) (*entity.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	if order.Status != entity.OrderStatusPaid && order.Status != entity.OrderStatusPartiallyRefunded {
+		return nil, ErrOrderNotPaid
+	}
+	return order, nil
+}
+
+// heldByBuyer reports whether the buyer still holds the ticket; tickets from
+// before holders were recorded always are.
+func heldByBuyer(order *entity.Order, ticket *entity.Ticket) bool {
+	return ticket.HolderID == nil || *ticket.HolderID == order.UserID
+}
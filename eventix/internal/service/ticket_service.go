@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketNotFound    = errors.New("ticket not found")
+	ErrTicketAlreadyUsed = errors.New("ticket already used")
+	ErrTicketWrongEvent  = errors.New("ticket does not belong to this event")
+	ErrOrderNotPaid      = errors.New("order has not been paid")
+	ErrTicketRefunded    = errors.New("ticket has been refunded")
+)
+
+type TicketService interface {
+	CheckIn(eventID uint, ticketCode string) (*entity.Ticket, error)
+	GetCheckInStats(eventID uint) (*entity.CheckInStats, error)
+	GetUserTickets(userID uint) ([]entity.Ticket, error)
+	GetTicketKeys(eventID uint) (*utils.JWKS, error)
+	GetRevokedCodes(eventID uint) ([]entity.RevokedTicketCode, error)
+	// EnsureTicketHolders records buyers as holders of tickets issued before transfers existed
+	EnsureTicketHolders() error
+}
+
+type ticketService struct {
+	ticketRepo repository.TicketRepository
+	eventRepo  repository.EventRepository
+}
+
+func NewTicketService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository) TicketService {
+	return &ticketService{
+		ticketRepo: ticketRepo,
+		eventRepo:  eventRepo,
+	}
+}
+
+// CheckIn validates a scanned ticket code at the gate of the given event and
+// marks the ticket as used. The returned ticket is still populated when the
+// ticket was already used so scanners can show when it was admitted.
+func (s *ticketService) CheckIn(eventID uint, ticketCode string) (*entity.Ticket, error) {
+	// Step 1: Look up the scanned code
+	ticket, err := s.ticketRepo.FindByTicketCode(ticketCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, err
+	}
+
+	// Step 2: Validate the ticket against the event being scanned
+	if ticket.EventID != eventID {
+		return nil, ErrTicketWrongEvent
+	}
+
+	if ticket.Status == entity.TicketStatusRefunded {
+		return nil, ErrTicketRefunded
+	}
+
+	if ticket.Order.Status != entity.OrderStatusPaid && ticket.Order.Status != entity.OrderStatusPartiallyRefunded {
+		return nil, ErrOrderNotPaid
+	}
+
+	if ticket.Status == entity.TicketStatusUsed {
+		return ticket, ErrTicketAlreadyUsed
+	}
+
+	// Step 3: Atomically flip VALID -> USED; losing a race with another
+	// scanner is reported the same way as an already used ticket
+	now := time.Now()
+	if err := s.ticketRepo.MarkAsUsed(ticket.ID, now); err != nil {
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return s.lostCheckInRace(ticket.ID)
+		}
+		return nil, err
+	}
+
+	ticket.Status = entity.TicketStatusUsed
+	ticket.CheckedInAt = &now
+	return ticket, nil
+}
+
+// lostCheckInRace reloads a ticket whose VALID -> USED update matched no row,
+// so the response reflects whoever changed it first: the other scanner's
+// check-in time, or a refund that landed in between.
+func (s *ticketService) lostCheckInRace(ticketID uint) (*entity.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == entity.TicketStatusRefunded {
+		return nil, ErrTicketRefunded
+	}
+	return ticket, ErrTicketAlreadyUsed
+}
+
+func (s *ticketService) GetCheckInStats(eventID uint) (*entity.CheckInStats, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	total, err := s.ticketRepo.CountByEventID(eventID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, err := s.ticketRepo.CountByEventID(eventID, entity.TicketStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	total -= refunded
+
+	checkedIn, err := s.ticketRepo.CountByEventID(eventID, entity.TicketStatusUsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.CheckInStats{
+		EventID:      eventID,
+		TotalTickets: total,
+		CheckedIn:    checkedIn,
+		Remaining:    total - checkedIn,
+	}, nil
+}
+
+// GetUserTickets lists the tickets the user currently holds, including ones
+// transferred to them.
+func (s *ticketService) GetUserTickets(userID uint) ([]entity.Ticket, error) {
+	return s.ticketRepo.FindByHolderID(userID)
+}
+
+// GetTicketKeys returns the public keys scanners verify the event's ticket
+// codes with while offline.
+func (s *ticketService) GetTicketKeys(eventID uint) (*utils.JWKS, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	signer, err := utils.TicketCodeSigner()
+	if err != nil {
+		return nil, err
+	}
+	return &utils.JWKS{Keys: []utils.JWK{signer.PublicKey(eventID)}}, nil
+}
+
+// GetRevokedCodes returns the correctly signed codes of the event that must
+// still be refused, for scanners to sync before going offline.
+func (s *ticketService) GetRevokedCodes(eventID uint) ([]entity.RevokedTicketCode, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+	return s.ticketRepo.FindRevokedCodes(eventID)
+}
+
+func (s *ticketService) EnsureTicketHolders() error {
+	updated, err := s.ticketRepo.BackfillHolders()
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		log.Printf("[Tickets] Recorded holders for %d ticket(s)", updated)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/worker"
+)
+
+var (
+	ErrTransferNotFound   = errors.New("transfer not found")
+	ErrTransferNotPending = errors.New("transfer is no longer pending")
+	ErrTransferPending    = errors.New("ticket already has a pending transfer")
+	ErrTransferToSelf     = errors.New("cannot transfer a ticket to yourself")
+	ErrEventStarted       = errors.New("event has already started")
+)
+
+// TicketTransferService moves tickets between users. A transfer is offered
+// to an email address and only takes effect when the user with that email
+// accepts it, at which point the ticket gets a new code.
+type TicketTransferService interface {
+	InitiateTransfer(userID, ticketID uint, input *entity.TransferTicketInput) (*entity.TicketTransfer, error)
+	AcceptTransfer(userID, transferID uint) (*entity.Ticket, error)
+	DeclineTransfer(userID, transferID uint) error
+	CancelTransfer(userID, transferID uint) error
+	GetUserTransfers(userID uint) ([]entity.TicketTransfer, error)
+	GetTicketHistory(actor entity.Actor, ticketID uint) ([]entity.TicketTransfer, error)
+}
+
+type ticketTransferService struct {
+	ticketRepo   repository.TicketRepository
+	transferRepo repository.TicketTransferRepository
+	userRepo     repository.UserRepository
+	outboxRepo   repository.OutboxRepository
+}
+
+func NewTicketTransferService(
+	ticketRepo repository.TicketRepository,
+	transferRepo repository.TicketTransferRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+) TicketTransferService {
+	return &ticketTransferService{
+		ticketRepo:   ticketRepo,
+		transferRepo: transferRepo,
+		userRepo:     userRepo,
+		outboxRepo:   outboxRepo,
+	}
+}
+
+// InitiateTransfer offers a ticket the user holds to another email address
+// and notifies the recipient.
+func (s *ticketTransferService) InitiateTransfer(userID, ticketID uint, input *entity.TransferTicketInput) (*entity.TicketTransfer, error) {
+	sender, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(sender.Email, input.Email) {
+		return nil, ErrTransferToSelf
+	}
+
+	db := s.ticketRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 1: Lock the ticket and check it can still change hands
+	ticket, err := s.ticketRepo.FindByIDForUpdate(tx, ticketID)
+	if err != nil || ticketHolder(ticket) != userID {
+		tx.Rollback()
+		return nil, ErrTicketNotFound
+	}
+
+	if err := checkTransferable(ticket, time.Now()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := s.transferRepo.FindPendingByTicketID(tx, ticket.ID); err == nil {
+		tx.Rollback()
+		return nil, ErrTransferPending
+	}
+
+	// Step 2: Record the transfer and notify the recipient
+	transfer := &entity.TicketTransfer{
+		TicketID:      ticket.ID,
+		EventID:       ticket.EventID,
+		FromUserID:    userID,
+		ToEmail:       strings.ToLower(input.Email),
+		Status:        entity.TransferStatusPending,
+		OldTicketCode: ticket.TicketCode,
+	}
+	if err := s.transferRepo.Save(tx, transfer); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	transfer.Event = ticket.Event
+	if err := s.outboxRepo.Enqueue(tx, entity.OutboxTopicTicketTransfer, worker.TicketTransferJob{
+		Transfer:   *transfer,
+		SenderName: sender.Name,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 3: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// AcceptTransfer makes the user the ticket's holder and reissues it under a
+// new code, so the code the sender knew stops working at the gate.
+func (s *ticketTransferService) AcceptTransfer(userID, transferID uint) (*entity.Ticket, error) {
+	recipient, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.ticketRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 1: Lock the transfer and check it is addressed to this user
+	transfer, err := s.transferRepo.FindByIDForUpdate(tx, transferID)
+	if err != nil || !strings.EqualFold(transfer.ToEmail, recipient.Email) {
+		tx.Rollback()
+		return nil, ErrTransferNotFound
+	}
+	if transfer.Status != entity.TransferStatusPending {
+		tx.Rollback()
+		return nil, ErrTransferNotPending
+	}
+
+	// Step 2: Lock the ticket; it may have been used or refunded meanwhile
+	ticket, err := s.ticketRepo.FindByIDForUpdate(tx, transfer.TicketID)
+	if err != nil {
+		tx.Rollback()
+		return nil, ErrTicketNotFound
+	}
+	if err := checkTransferable(ticket, time.Now()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 3: Reissue the ticket to the recipient; the old code is revoked
+	now := time.Now()
+	reissued := *ticket
+	reissued.HolderID = &userID
+	newCode, err := signTicketCode(&reissued, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.ticketRepo.Reissue(tx, ticket.ID, ticket.TicketCode, newCode, userID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return nil, ErrTicketAlreadyUsed
+		}
+		return nil, err
+	}
+
+	if err := s.transferRepo.Respond(tx, transfer.ID, entity.TransferStatusAccepted, &userID, now); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 4: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	ticket.TicketCode = newCode
+	ticket.HolderID = &userID
+	return ticket, nil
+}
+
+func (s *ticketTransferService) DeclineTransfer(userID, transferID uint) error {
+	recipient, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.closeTransfer(transferID, entity.TransferStatusDeclined, &userID, func(transfer *entity.TicketTransfer) bool {
+		return strings.EqualFold(transfer.ToEmail, recipient.Email)
+	})
+}
+
+func (s *ticketTransferService) CancelTransfer(userID, transferID uint) error {
+	return s.closeTransfer(transferID, entity.TransferStatusCancelled, nil, func(transfer *entity.TicketTransfer) bool {
+		return transfer.FromUserID == userID
+	})
+}
+
+func (s *ticketTransferService) GetUserTransfers(userID uint) ([]entity.TicketTransfer, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transferRepo.FindByUser(userID, user.Email)
+}
+
+// GetTicketHistory returns every transfer of a ticket. It is visible to the
+// current holder and to staff who can read all orders.
+func (s *ticketTransferService) GetTicketHistory(actor entity.Actor, ticketID uint) ([]entity.TicketTransfer, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticketHolder(ticket) != actor.UserID && !actor.Can(entity.PermissionOrdersReadAll) {
+		return nil, ErrTicketNotFound
+	}
+	return s.transferRepo.FindByTicketID(ticketID)
+}
+
+// closeTransfer ends a pending transfer without moving the ticket. allowed
+// decides whether the caller may do so.
+func (s *ticketTransferService) closeTransfer(transferID uint, status entity.TransferStatus, toUserID *uint, allowed func(*entity.TicketTransfer) bool) error {
+	db := s.ticketRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := s.transferRepo.FindByIDForUpdate(tx, transferID)
+	if err != nil || !allowed(transfer) {
+		tx.Rollback()
+		return ErrTransferNotFound
+	}
+
+	if err := s.transferRepo.Respond(tx, transfer.ID, status, toUserID, time.Now()); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrNoRowsAffected) {
+			return ErrTransferNotPending
+		}
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ticketHolder returns who holds the ticket; tickets issued before holders
+// were recorded belong to the buyer.
+func ticketHolder(ticket *entity.Ticket) uint {
+	if ticket.HolderID != nil {
+		return *ticket.HolderID
+	}
+	return ticket.Order.UserID
+}
+
+func checkTransferable(ticket *entity.Ticket, now time.Time) error {
+	if ticket.Status == entity.TicketStatusUsed {
+		return ErrTicketAlreadyUsed
+	}
+	if ticket.Status == entity.TicketStatusRefunded {
+		return ErrTicketRefunded
+	}
+	if ticket.Order.Status != entity.OrderStatusPaid && ticket.Order.Status != entity.OrderStatusPartiallyRefunded {
+		return ErrOrderNotPaid
+	}
+	if !now.Before(ticket.Event.Date) {
+		return ErrEventStarted
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+)
+
+var (
+	ErrVenueNotFound          = errors.New("venue not found")
+	ErrInvalidSeatLayout      = errors.New("section names and row labels must be unique, and flagged seats must exist in their row")
+	ErrSectionNotInVenue      = errors.New("section does not belong to this venue")
+	ErrInvalidSeatingPlan     = errors.New("each section can only be assigned once")
+	ErrSeatingConfigured      = errors.New("event already has a seat map")
+	ErrSeatingNotConfigured   = errors.New("event has no seat map")
+	ErrSeatNotFound           = errors.New("seat is not on sale for this event")
+	ErrSeatUnavailable        = errors.New("seat is no longer available")
+	ErrSeatSelectionRequired  = errors.New("seated ticket types are booked by choosing seats")
+	ErrSeatsWithQuantity      = errors.New("book either seats or quantities, not both")
+	ErrSeatedTicketType       = errors.New("ticket type is sold by seat")
+	ErrSeatedTicketTypeResize = errors.New("seated ticket types are sized by their seats")
+)
+
+// VenueService manages venue seat maps and puts events on sale seat by seat.
+type VenueService interface {
+	CreateVenue(input *entity.CreateVenueInput) (*entity.Venue, error)
+	GetVenue(id uint) (*entity.Venue, error)
+	ListVenues() ([]entity.Venue, error)
+	ConfigureSeating(actor entity.Actor, eventID uint, input *entity.ConfigureSeatingInput) ([]entity.SeatAvailability, error)
+	GetSeatMap(eventID uint) ([]entity.SeatAvailability, error)
+}
+
+type venueService struct {
+	venueRepo      repository.VenueRepository
+	eventSeatRepo  repository.EventSeatRepository
+	eventRepo      repository.EventRepository
+	ticketTypeRepo repository.TicketTypeRepository
+	waitlistRepo   repository.WaitlistRepository
+}
+
+func NewVenueService(
+	venueRepo repository.VenueRepository,
+	eventSeatRepo repository.EventSeatRepository,
+	eventRepo repository.EventRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	waitlistRepo repository.WaitlistRepository,
+) VenueService {
+	return &venueService{
+		venueRepo:      venueRepo,
+		eventSeatRepo:  eventSeatRepo,
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		waitlistRepo:   waitlistRepo,
+	}
+}
+
+// CreateVenue builds the seat map from its rows, numbering the seats of each
+// row from 1.
+func (s *venueService) CreateVenue(input *entity.CreateVenueInput) (*entity.Venue, error) {
+	venue := &entity.Venue{
+		Name:     input.Name,
+		Address:  input.Address,
+		Sections: make([]entity.VenueSection, 0, len(input.Sections)),
+	}
+
+	sectionNames := make(map[string]bool)
+	for _, sectionInput := range input.Sections {
+		key := strings.ToLower(sectionInput.Name)
+		if sectionNames[key] {
+			return nil, ErrInvalidSeatLayout
+		}
+		sectionNames[key] = true
+
+		section := entity.VenueSection{Name: sectionInput.Name}
+		rowLabels := make(map[string]bool)
+		for _, row := range sectionInput.Rows {
+			key := strings.ToUpper(row.Label)
+			if rowLabels[key] {
+				return nil, ErrInvalidSeatLayout
+			}
+			rowLabels[key] = true
+
+			accessible, ok := seatNumberSet(row.Accessible, row.Seats)
+			if !ok {
+				return nil, ErrInvalidSeatLayout
+			}
+			companion, ok := seatNumberSet(row.Companion, row.Seats)
+			if !ok {
+				return nil, ErrInvalidSeatLayout
+			}
+
+			for number := 1; number <= row.Seats; number++ {
+				section.Seats = append(section.Seats, entity.Seat{
+					Row:        row.Label,
+					Number:     number,
+					Label:      fmt.Sprintf("%s, Row %s, Seat %d", section.Name, row.Label, number),
+					Accessible: accessible[number],
+					Companion:  companion[number],
+				})
+			}
+		}
+		venue.Sections = append(venue.Sections, section)
+	}
+
+	if err := s.venueRepo.Save(venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func (s *venueService) GetVenue(id uint) (*entity.Venue, error) {
+	venue, err := s.venueRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	return venue, nil
+}
+
+func (s *venueService) ListVenues() ([]entity.Venue, error) {
+	return s.venueRepo.FindAll()
+}
+
+// ConfigureSeating puts the seats of the listed venue sections on sale for the
+// event, each section through one of the event's ticket types. Those tiers
+// become seated and are resized to their number of seats, so they must not
+// have been booked yet or have a waitlist. An event's seat map is set once.
+func (s *venueService) ConfigureSeating(actor entity.Actor, eventID uint, input *entity.ConfigureSeatingInput) ([]entity.SeatAvailability, error) {
+	// Step 1: Validate the plan against the event and the venue
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if !canManageEvent(actor, event) {
+		return nil, ErrEventForbidden
+	}
+
+	venue, err := s.venueRepo.FindByID(input.VenueID)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	sections := make(map[uint]*entity.VenueSection, len(venue.Sections))
+	for i := range venue.Sections {
+		sections[venue.Sections[i].ID] = &venue.Sections[i]
+	}
+	tiers := make(map[uint]bool, len(event.TicketTypes))
+	for _, tier := range event.TicketTypes {
+		tiers[tier.ID] = true
+	}
+
+	var eventSeats []entity.EventSeat
+	seatsPerTier := make(map[uint]int)
+	assigned := make(map[uint]bool)
+	for _, plan := range input.Sections {
+		section, ok := sections[plan.SectionID]
+		if !ok {
+			return nil, ErrSectionNotInVenue
+		}
+		if assigned[plan.SectionID] {
+			return nil, ErrInvalidSeatingPlan
+		}
+		assigned[plan.SectionID] = true
+		if !tiers[plan.TicketTypeID] {
+			return nil, ErrTicketTypeNotFound
+		}
+
+		for _, seat := range section.Seats {
+			eventSeats = append(eventSeats, entity.EventSeat{
+				EventID:      eventID,
+				SeatID:       seat.ID,
+				TicketTypeID: plan.TicketTypeID,
+				Status:       entity.SeatStatusAvailable,
+			})
+		}
+		seatsPerTier[plan.TicketTypeID] += len(section.Seats)
+	}
+
+	// Step 2: Start database transaction
+	db := s.eventRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Step 3: Size the seated tiers by their seats; the row locks keep
+	// concurrent bookings out until the seats exist. Tiers are locked in ID
+	// order like bookings do, so the two cannot deadlock
+	ticketTypeIDs := make([]uint, 0, len(seatsPerTier))
+	for ticketTypeID := range seatsPerTier {
+		ticketTypeIDs = append(ticketTypeIDs, ticketTypeID)
+	}
+	sort.Slice(ticketTypeIDs, func(i, j int) bool { return ticketTypeIDs[i] < ticketTypeIDs[j] })
+
+	delta := 0
+	for _, ticketTypeID := range ticketTypeIDs {
+		count := seatsPerTier[ticketTypeID]
+		tier, err := s.ticketTypeRepo.FindByIDForUpdate(tx, ticketTypeID)
+		if err != nil {
+			tx.Rollback()
+			return nil, ErrTicketTypeNotFound
+		}
+		if tier.AvailableQuantity != tier.TotalQuantity {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: %s", ErrTicketTypeInUse, tier.Name)
+		}
+		// Waitlists are kept by quantity, which a seated tier cannot honour
+		waiting, err := s.waitlistRepo.HasActive(tx, tier.ID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if waiting {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: %s", ErrTicketTypeInUse, tier.Name)
+		}
+		delta += count - tier.TotalQuantity
+		tier.TotalQuantity = count
+		tier.AvailableQuantity = count
+		tier.Seated = true
+		if err := s.ticketTypeRepo.Update(tx, tier); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Step 4: Lock the event so concurrent configurations run one after the
+	// other, then put the seats on sale and resize its pool with the tiers
+	locked, err := s.eventRepo.FindByIDForUpdate(tx, eventID)
+	if err != nil {
+		tx.Rollback()
+		return nil, ErrEventNotFound
+	}
+
+	configured, err := s.eventSeatRepo.CountByEventID(tx, eventID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if configured > 0 {
+		tx.Rollback()
+		return nil, ErrSeatingConfigured
+	}
+
+	if err := s.eventSeatRepo.SaveBatch(tx, eventSeats); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	locked.VenueID = &venue.ID
+	locked.TotalTickets += delta
+	locked.AvailableTickets += delta
+	if err := s.eventRepo.Update(tx, locked); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Step 5: Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.eventSeatRepo.FindAvailability(eventID)
+}
+
+func (s *venueService) GetSeatMap(eventID uint) ([]entity.SeatAvailability, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	seats, err := s.eventSeatRepo.FindAvailability(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(seats) == 0 {
+		return nil, ErrSeatingNotConfigured
+	}
+	return seats, nil
+}
+
+// seatNumberSet indexes seat numbers of a row, rejecting numbers outside it.
+func seatNumberSet(numbers []int, seats int) (map[int]bool, bool) {
+	set := make(map[int]bool, len(numbers))
+	for _, number := range numbers {
+		if number < 1 || number > seats {
+			return nil, false
+		}
+		set[number] = true
+	}
+	return set, true
+}
+
+// findBookableSeats loads the chosen seats of the event, failing fast when one
+// is unknown or already taken.
+func findBookableSeats(eventSeatRepo repository.EventSeatRepository, eventID uint, seatIDs []uint) ([]entity.EventSeat, error) {
+	unique := make(map[uint]bool, len(seatIDs))
+	for _, seatID := range seatIDs {
+		unique[seatID] = true
+	}
+
+	seats, err := eventSeatRepo.FindBySeatIDs(eventID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(seats) != len(unique) {
+		return nil, ErrSeatNotFound
+	}
+	for _, seat := range seats {
+		if seat.Status != entity.SeatStatusAvailable {
+			return nil, ErrSeatUnavailable
+		}
+	}
+	return seats, nil
+}
+
+func seatIDsOf(seats []entity.EventSeat) []uint {
+	ids := make([]uint, len(seats))
+	for i, seat := range seats {
+		ids[i] = seat.SeatID
+	}
+	return ids
+}
+
+// seatBookingItems turns the chosen seats into one booking line per tier.
+func seatBookingItems(seats []entity.EventSeat) []entity.BookingItem {
+	counts := make(map[uint]int)
+	var order []uint
+	for _, seat := range seats {
+		if counts[seat.TicketTypeID] == 0 {
+			order = append(order, seat.TicketTypeID)
+		}
+		counts[seat.TicketTypeID]++
+	}
+
+	items := make([]entity.BookingItem, 0, len(order))
+	for _, ticketTypeID := range order {
+		items = append(items, entity.BookingItem{TicketTypeID: ticketTypeID, Quantity: counts[ticketTypeID]})
+	}
+	return items
+}
+
+// checkSeatSelection rejects quantity bookings of seated tiers, which would
+// sell tickets without a seat.
+func checkSeatSelection(event *entity.Event, items []entity.OrderItem, seats []entity.EventSeat) error {
+	seated := make(map[uint]bool)
+	for _, tier := range event.TicketTypes {
+		seated[tier.ID] = tier.Seated
+	}
+
+	chosen := make(map[uint]int)
+	for _, seat := range seats {
+		chosen[seat.TicketTypeID]++
+	}
+	for _, item := range items {
+		if seated[item.TicketTypeID] && chosen[item.TicketTypeID] != item.Quantity {
+			return fmt.Errorf("%w: %s", ErrSeatSelectionRequired, item.TicketTypeName)
+		}
+	}
+	return nil
+}
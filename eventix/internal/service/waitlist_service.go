@@ -0,0 +1,447 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/internal/repository"
+	"eventix/pkg/worker"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrAlreadyOnWaitlist     = errors.New("already on the waitlist for this ticket type")
+	ErrTicketsStillAvailable = errors.New("tickets are still available, book them instead")
+	ErrWaitlistNotActive     = errors.New("waitlist entry is no longer active")
+	ErrNoWaitlistOffer       = errors.New("waitlist entry has no open offer")
+	ErrWaitlistOfferExpired  = errors.New("waitlist offer has expired")
+)
+
+// waitlistBatchSize caps how many entries or ticket types one pass handles.
+const waitlistBatchSize = 100
+
+type WaitlistService interface {
+	JoinWaitlist(userID, eventID uint, input *entity.JoinWaitlistInput) (*entity.WaitlistEntry, error)
+	LeaveWaitlist(userID, entryID uint) error
+	GetUserWaitlist(userID uint) ([]entity.WaitlistEntry, error)
+	ProcessWaitlists() (int, error)
+}
+
+type waitlistService struct {
+	waitlistRepo repository.WaitlistRepository
+	eventRepo    repository.EventRepository
+	inventory    *inventoryManager
+	offers       *waitlistOfferer
+	limits       *purchaseLimiter
+}
+
+func NewWaitlistService(
+	waitlistRepo repository.WaitlistRepository,
+	eventRepo repository.EventRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	orderRepo repository.OrderRepository,
+	holdRepo repository.HoldRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	offerWindow time.Duration,
+	maxTicketsPerUser int,
+) WaitlistService {
+	inventory := &inventoryManager{
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+	}
+	limits := &purchaseLimiter{
+		orderRepo:    orderRepo,
+		holdRepo:     holdRepo,
+		waitlistRepo: waitlistRepo,
+		userRepo:     userRepo,
+		defaultLimit: maxTicketsPerUser,
+	}
+	return &waitlistService{
+		waitlistRepo: waitlistRepo,
+		eventRepo:    eventRepo,
+		inventory:    inventory,
+		offers:       newWaitlistOfferer(waitlistRepo, eventRepo, ticketTypeRepo, userRepo, outboxRepo, inventory, limits, offerWindow),
+		limits:       limits,
+	}
+}
+
+// JoinWaitlist puts the user in line for a ticket type that cannot cover the
+// requested quantity right now.
+func (s *waitlistService) JoinWaitlist(userID, eventID uint, input *entity.JoinWaitlistInput) (*entity.WaitlistEntry, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	ticketTypeID := input.TicketTypeID
+	if ticketTypeID == 0 {
+		if len(event.TicketTypes) != 1 {
+			return nil, ErrTicketTypeRequired
+		}
+		ticketTypeID = event.TicketTypes[0].ID
+	}
+
+	var tier *entity.TicketType
+	for i := range event.TicketTypes {
+		if event.TicketTypes[i].ID == ticketTypeID {
+			tier = &event.TicketTypes[i]
+			break
+		}
+	}
+	if tier == nil {
+		return nil, ErrTicketTypeNotFound
+	}
+	if tier.Seated {
+		return nil, ErrSeatedTicketType
+	}
+
+	if (tier.SalesEnd != nil && time.Now().After(*tier.SalesEnd)) || !time.Now().Before(event.Date) {
+		return nil, fmt.Errorf("%w: %s", ErrTicketTypeNotOnSale, tier.Name)
+	}
+	if tier.MaxPerOrder > 0 && input.Quantity > tier.MaxPerOrder {
+		return nil, fmt.Errorf("%w: at most %d %s per order", ErrTicketTypeLimitExceeded, tier.MaxPerOrder, tier.Name)
+	}
+	if tier.AvailableQuantity >= input.Quantity && event.AvailableTickets >= input.Quantity {
+		return nil, ErrTicketsStillAvailable
+	}
+	// Checked again when an offer is made and when it is claimed
+	if err := s.limits.precheck(userID, event, input.Quantity); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.waitlistRepo.FindActive(userID, tier.ID); err == nil {
+		return nil, ErrAlreadyOnWaitlist
+	}
+
+	entry := &entity.WaitlistEntry{
+		EventID:      event.ID,
+		TicketTypeID: tier.ID,
+		UserID:       userID,
+		Quantity:     input.Quantity,
+		Status:       entity.WaitlistStatusWaiting,
+	}
+	if err := s.waitlistRepo.Save(nil, entry); err != nil {
+		return nil, err
+	}
+
+	position, err := s.waitlistRepo.CountAhead(entry)
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = int(position) + 1
+	return entry, nil
+}
+
+// LeaveWaitlist removes the user from the line. Leaving with an open offer
+// declines it, and the held tickets go to the next person in line.
+func (s *waitlistService) LeaveWaitlist(userID, entryID uint) error {
+	db := s.waitlistRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	entry, err := s.waitlistRepo.FindByIDForUpdate(tx, entryID)
+	if err != nil || entry.UserID != userID {
+		tx.Rollback()
+		return ErrWaitlistEntryNotFound
+	}
+
+	if entry.Status != entity.WaitlistStatusWaiting && entry.Status != entity.WaitlistStatusOffered {
+		tx.Rollback()
+		return ErrWaitlistNotActive
+	}
+
+	if err := s.waitlistRepo.TransitionStatus(tx, entry.ID, entry.Status, entity.WaitlistStatusLeft); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if entry.Status == entity.WaitlistStatusOffered {
+		if err := s.passOn(tx, entry); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *waitlistService) GetUserWaitlist(userID uint) ([]entity.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].Status != entity.WaitlistStatusWaiting {
+			continue
+		}
+		ahead, err := s.waitlistRepo.CountAhead(&entries[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Position = int(ahead) + 1
+	}
+	return entries, nil
+}
+
+// ProcessWaitlists expires offers that were not claimed in time, passing their
+// tickets on, and offers tickets that became available outside of a release,
+// e.g. when an organizer adds inventory. Each entry and ticket type is handled
+// in its own transaction. It returns the number of offers made.
+func (s *waitlistService) ProcessWaitlists() (int, error) {
+	expired, err := s.waitlistRepo.FindExpiredOffers(time.Now(), waitlistBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for _, entry := range expired {
+		n, err := s.expireOffer(entry.ID)
+		if err != nil {
+			log.Printf("[WaitlistService] Failed to expire offer %d: %v", entry.ID, err)
+			continue
+		}
+		offered += n
+	}
+
+	ticketTypeIDs, err := s.waitlistRepo.FindTicketTypesToOffer(waitlistBatchSize)
+	if err != nil {
+		return offered, err
+	}
+
+	for _, ticketTypeID := range ticketTypeIDs {
+		n, err := s.offerAvailable(ticketTypeID)
+		if err != nil {
+			log.Printf("[WaitlistService] Failed to offer ticket type %d: %v", ticketTypeID, err)
+			continue
+		}
+		offered += n
+	}
+
+	return offered, nil
+}
+
+func (s *waitlistService) expireOffer(entryID uint) (int, error) {
+	db := s.waitlistRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Re-check under the lock; the offer may have been claimed meanwhile
+	entry, err := s.waitlistRepo.FindByIDForUpdate(tx, entryID)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if entry.Status != entity.WaitlistStatusOffered || entry.OfferExpiresAt == nil || time.Now().Before(*entry.OfferExpiresAt) {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	if err := s.waitlistRepo.TransitionStatus(tx, entry.ID, entity.WaitlistStatusOffered, entity.WaitlistStatusExpired); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := s.inventory.releaseItems(tx, entry.EventID, heldItems(entry)); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	offered, err := s.offers.offer(tx, entry.TicketTypeID, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return offered, tx.Commit().Error
+}
+
+func (s *waitlistService) offerAvailable(ticketTypeID uint) (int, error) {
+	db := s.waitlistRepo.GetDB()
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	offered, err := s.offers.offer(tx, ticketTypeID, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return offered, tx.Commit().Error
+}
+
+// passOn returns the tickets held for an entry and offers them to the next
+// person in line.
+func (s *waitlistService) passOn(tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	if err := s.inventory.releaseItems(tx, entry.EventID, heldItems(entry)); err != nil {
+		return err
+	}
+	_, err := s.offers.offer(tx, entry.TicketTypeID, time.Now())
+	return err
+}
+
+// waitlistOfferer hands tickets that come back to the people waiting for
+// them. Every method runs inside the caller's transaction.
+type waitlistOfferer struct {
+	waitlistRepo   repository.WaitlistRepository
+	eventRepo      repository.EventRepository
+	ticketTypeRepo repository.TicketTypeRepository
+	userRepo       repository.UserRepository
+	outboxRepo     repository.OutboxRepository
+	inventory      *inventoryManager
+	limits         *purchaseLimiter
+	offerWindow    time.Duration
+}
+
+func newWaitlistOfferer(
+	waitlistRepo repository.WaitlistRepository,
+	eventRepo repository.EventRepository,
+	ticketTypeRepo repository.TicketTypeRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	inventory *inventoryManager,
+	limits *purchaseLimiter,
+	offerWindow time.Duration,
+) *waitlistOfferer {
+	return &waitlistOfferer{
+		waitlistRepo:   waitlistRepo,
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		userRepo:       userRepo,
+		outboxRepo:     outboxRepo,
+		inventory:      inventory,
+		limits:         limits,
+		offerWindow:    offerWindow,
+	}
+}
+
+// offer holds a tier's available tickets for waiting entries in line order
+// and queues a notification for each. Entries asking for more than is left
+// are skipped rather than blocking everyone behind them, and so are users
+// whose per-user limit leaves no room for the tickets. Nothing is offered
+// for seated tiers, which are sold by seat, or once the tier is off sale or
+// the event has taken place. The tier and event
+// rows are locked first so concurrent offers cannot hand out the same
+// tickets. It returns the number of offers made.
+func (o *waitlistOfferer) offer(tx *gorm.DB, ticketTypeID uint, now time.Time) (int, error) {
+	tier, err := o.ticketTypeRepo.FindByIDForUpdate(tx, ticketTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if tier.Seated || tier.AvailableQuantity <= 0 {
+		return 0, nil
+	}
+
+	event, err := o.eventRepo.FindByIDForUpdate(tx, tier.EventID)
+	if err != nil {
+		return 0, err
+	}
+	if !salesOpen(event, tier, now) {
+		return 0, nil
+	}
+
+	available := tier.AvailableQuantity
+	if event.AvailableTickets < available {
+		available = event.AvailableTickets
+	}
+	if available <= 0 {
+		return 0, nil
+	}
+
+	entries, err := o.waitlistRepo.FindWaitingForUpdate(tx, tier.ID, waitlistBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for i := range entries {
+		if available == 0 {
+			break
+		}
+		entry := &entries[i]
+		if entry.Quantity > available {
+			continue
+		}
+		room, err := o.limits.hasRoom(tx, entry.UserID, event, entry.Quantity)
+		if err != nil {
+			return offered, err
+		}
+		if !room {
+			continue
+		}
+
+		if err := o.inventory.reserve(tx, event.ID, heldItems(entry)); err != nil {
+			return offered, err
+		}
+
+		expiresAt := now.Add(o.offerWindow)
+		if err := o.waitlistRepo.MarkOffered(tx, entry.ID, now, expiresAt); err != nil {
+			return offered, err
+		}
+
+		user, err := o.userRepo.FindByID(entry.UserID)
+		if err != nil {
+			return offered, err
+		}
+
+		entry.Status = entity.WaitlistStatusOffered
+		entry.OfferedAt = &now
+		entry.OfferExpiresAt = &expiresAt
+		entry.Event = *event
+		entry.TicketType = *tier
+		if err := o.outboxRepo.Enqueue(tx, entity.OutboxTopicWaitlistOffer, worker.WaitlistOfferJob{
+			Entry: *entry,
+			Email: user.Email,
+			Name:  user.Name,
+		}); err != nil {
+			return offered, err
+		}
+
+		available -= entry.Quantity
+		offered++
+	}
+
+	return offered, nil
+}
+
+// heldItems describes the tickets an offered entry holds as order items, the
+// unit inventoryManager works in.
+func heldItems(entry *entity.WaitlistEntry) []entity.OrderItem {
+	return []entity.OrderItem{{
+		TicketTypeID: entry.TicketTypeID,
+		Quantity:     entry.Quantity,
+	}}
+}
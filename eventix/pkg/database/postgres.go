@@ -46,3 +46,16 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// Close releases the connection pool.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	log.Println("Database connection pool closed")
+	return nil
+}
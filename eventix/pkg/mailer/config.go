@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"eventix/pkg/utils"
+)
+
+const defaultFrom = "Eventix <no-reply@eventix.local>"
+
+// NewFromEnv builds the mailer selected by MAIL_DRIVER: "smtp" for real
+// delivery, "file" (default) to write .eml files to MAIL_DIR, or "memory".
+func NewFromEnv() (Mailer, error) {
+	from := utils.GetEnv("MAIL_FROM", defaultFrom)
+
+	switch driver := utils.GetEnv("MAIL_DRIVER", "file"); driver {
+	case "smtp":
+		config := SMTPConfig{
+			Host:     utils.GetEnv("SMTP_HOST", "localhost"),
+			Port:     utils.GetEnv("SMTP_PORT", "587"),
+			Username: utils.GetEnv("SMTP_USERNAME", ""),
+			Password: utils.GetEnv("SMTP_PASSWORD", ""),
+			From:     from,
+			Timeout:  utils.GetEnvDuration("SMTP_TIMEOUT", 30*time.Second),
+		}
+		log.Printf("[Mailer] Using SMTP relay %s:%s", config.Host, config.Port)
+		return NewSMTPMailer(config), nil
+	case "file":
+		dir := utils.GetEnv("MAIL_DIR", "./mail_outbox")
+		log.Printf("[Mailer] Writing emails to %s", dir)
+		return NewFileMailer(dir, from)
+	case "memory":
+		log.Println("[Mailer] Keeping emails in memory, nothing will be delivered")
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", driver)
+	}
+}
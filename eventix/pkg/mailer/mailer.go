@@ -0,0 +1,162 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is a rendered email with plain-text and HTML alternatives.
+type Message struct {
+	To          string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with a message.
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer delivers rendered messages.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// buildMIME encodes msg as a multipart/alternative RFC 5322 message, wrapped
+// in multipart/mixed when it has attachments.
+func buildMIME(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	messageID, err := newMessageID(from)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: %s\r\n", messageID)
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+
+	contentType, body, err := buildAlternative(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Attachments) == 0 {
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", contentType)
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mixed.Boundary())
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range msg.Attachments {
+		w, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.FileName})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(w, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildAlternative encodes the text and HTML bodies as a multipart/alternative
+// entity and returns its content type and body.
+func buildAlternative(msg Message) (string, []byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.TextBody},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("multipart/alternative; boundary=%q", writer.Boundary()), buf.Bytes(), nil
+}
+
+// writeBase64Lines base64-encodes data in lines of 76 characters as required
+// by RFC 2045.
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := 76
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+func newMessageID(from string) (string, error) {
+	bytes := make([]byte, 12)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	domain := "eventix.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(bytes), domain), nil
+}
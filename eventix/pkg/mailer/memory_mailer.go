@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"sync"
+)
+
+// MemoryMailer keeps sent messages in memory, for tests and local runs that
+// should not send anything.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		return t.Format("Monday, 02 January 2006 15:04 MST")
+	},
+	"money": func(amount float64) string {
+		return fmt.Sprintf("$%.2f", amount)
+	},
+}
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.txt"))
+)
+
+type OrderConfirmationData struct {
+	Name          string
+	OrderID       uint
+	EventTitle    string
+	EventDate     time.Time
+	EventLocation string
+	Quantity      int
+	TotalAmount   float64
+	TicketCodes   []string
+}
+
+func OrderConfirmation(to string, data OrderConfirmationData) (Message, error) {
+	subject := fmt.Sprintf("Your tickets for %s (Order #%d)", data.EventTitle, data.OrderID)
+	return render(to, subject, "order_confirmation", data)
+}
+
+type WaitlistOfferData struct {
+	Name           string
+	EntryID        uint
+	EventTitle     string
+	EventDate      time.Time
+	EventLocation  string
+	TicketTypeName string
+	Quantity       int
+	ExpiresAt      time.Time
+}
+
+func WaitlistOffer(to string, data WaitlistOfferData) (Message, error) {
+	subject := fmt.Sprintf("Tickets for %s are available for you", data.EventTitle)
+	return render(to, subject, "waitlist_offer", data)
+}
+
+type TicketTransferData struct {
+	SenderName    string
+	TransferID    uint
+	EventTitle    string
+	EventDate     time.Time
+	EventLocation string
+}
+
+func TicketTransfer(to string, data TicketTransferData) (Message, error) {
+	subject := fmt.Sprintf("%s sent you a ticket for %s", data.SenderName, data.EventTitle)
+	return render(to, subject, "ticket_transfer", data)
+}
+
+// render executes the <name>.txt and <name>.html templates into a Message.
+func render(to, subject, name string, data interface{}) (Message, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s text template: %w", name, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s html template: %w", name, err)
+	}
+
+	return Message{
+		To:       to,
+		Subject:  subject,
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
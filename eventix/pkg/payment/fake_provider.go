@@ -0,0 +1,113 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+type FakeMode string
+
+const (
+	FakeModeSucceed FakeMode = "succeed"
+	FakeModeDecline FakeMode = "decline"
+	FakeModeTimeout FakeMode = "timeout"
+	FakeModeAsync   FakeMode = "async"
+)
+
+// FakeProvider is a local stand-in for a real gateway. Its mode decides the
+// outcome of every authorization so failure paths can be exercised without
+// touching a real payment network.
+type FakeProvider struct {
+	mode  FakeMode
+	delay time.Duration
+
+	mu       sync.Mutex
+	refunded map[string]float64
+}
+
+func NewFakeProvider(mode FakeMode, delay time.Duration) *FakeProvider {
+	switch mode {
+	case FakeModeSucceed, FakeModeDecline, FakeModeTimeout, FakeModeAsync:
+	default:
+		log.Printf("Warning: unknown fake payment mode %q, using %q", mode, FakeModeSucceed)
+		mode = FakeModeSucceed
+	}
+	return &FakeProvider{mode: mode, delay: delay, refunded: make(map[string]float64)}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	switch p.mode {
+	case FakeModeDecline:
+		return nil, ErrDeclined
+	case FakeModeTimeout:
+		// Never answer; the caller's deadline decides when to give up
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	reference, err := newFakeReference()
+	if err != nil {
+		return nil, err
+	}
+	return &Authorization{Reference: reference, Pending: p.mode == FakeModeAsync}, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, reference string, amount float64) error {
+	return p.wait(ctx)
+}
+
+// Refund remembers the idempotency keys it has paid out, like a real gateway,
+// so a repeated refund is answered without paying again.
+func (p *FakeProvider) Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) error {
+	p.mu.Lock()
+	paid, seen := p.refunded[idempotencyKey]
+	p.mu.Unlock()
+	if seen {
+		if paid != amount {
+			return fmt.Errorf("idempotency key %s was already used for a refund of %.2f", idempotencyKey, paid)
+		}
+		return nil
+	}
+
+	if err := p.wait(ctx); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.refunded[idempotencyKey] = amount
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FakeProvider) wait(ctx context.Context) error {
+	if p.delay <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-time.After(p.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newFakeReference() (string, error) {
+	bytes := make([]byte, 12)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "fake_" + hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrDeclined          = errors.New("payment declined by provider")
+	ErrUnsupportedMethod = errors.New("unsupported payment method")
+)
+
+// AuthorizeRequest describes the charge a provider is asked to authorize.
+type AuthorizeRequest struct {
+	OrderID       uint
+	Amount        float64
+	PaymentMethod string
+}
+
+// Authorization is the provider's answer to a successful authorize call.
+// Reference identifies the payment on the provider side and is used for
+// capture and refund. Pending means the gateway accepted the request but will
+// confirm the outcome asynchronously through a webhook.
+type Authorization struct {
+	Reference string
+	Pending   bool
+}
+
+// PaymentProvider is implemented by every payment gateway integration.
+// Implementations must honour ctx cancellation so a slow gateway surfaces as a
+// timeout instead of blocking the request.
+//
+// Refund pays money back at most once per idempotencyKey: repeating a refund
+// with a key the gateway has already seen returns the outcome of the first
+// call instead of paying again, so a caller that lost the answer can retry.
+type PaymentProvider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	Capture(ctx context.Context, reference string, amount float64) error
+	Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) error
+}
+
+// Registry maps payment methods (as sent in PaymentInput.PaymentMethod) to the
+// provider that handles them.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]PaymentProvider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]PaymentProvider)}
+}
+
+func (r *Registry) Register(method string, provider PaymentProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[method] = provider
+}
+
+func (r *Registry) Get(method string) (PaymentProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+	return provider, nil
+}
+
+// GetByName returns the provider with the given Name, regardless of which
+// payment methods it is registered for.
+func (r *Registry) GetByName(name string) (PaymentProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, provider := range r.providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown payment provider: %s", name)
+}
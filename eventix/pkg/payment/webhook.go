@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookPaymentSucceeded = "payment.succeeded"
+	WebhookPaymentFailed    = "payment.failed"
+
+	// SignatureHeader carries "sha256=<hex HMAC of the raw request body>".
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookEvent is the notification a gateway sends when the outcome of an
+// asynchronous payment is known.
+type WebhookEvent struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	OrderID       uint      `json:"order_id"`
+	Reference     string    `json:"reference"`
+	Amount        float64   `json:"amount"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the HMAC-SHA256 signature of the raw body in constant time.
+func VerifySignature(secret string, body []byte, signature string) error {
+	if secret == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
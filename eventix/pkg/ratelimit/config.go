@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log"
+
+	"eventix/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// NewStoreFromEnv builds the store selected by RATE_LIMIT_STORE: "memory"
+// (default) for a single instance, or "postgres" to share limits between
+// instances through db.
+func NewStoreFromEnv(db *gorm.DB) (Store, error) {
+	switch driver := utils.GetEnv("RATE_LIMIT_STORE", "memory"); driver {
+	case "memory":
+		log.Println("[RateLimit] Keeping buckets in memory, limits apply per instance")
+		return NewMemoryStore(), nil
+	case "postgres":
+		log.Println("[RateLimit] Keeping buckets in Postgres, limits are shared by all instances")
+		return NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q", driver)
+	}
+}
+
+// LimitFromEnv reads a limit written for ParseLimit from the environment.
+func LimitFromEnv(key, fallback string) (Limit, error) {
+	limit, err := ParseLimit(utils.GetEnv(key, fallback))
+	if err != nil {
+		return Limit{}, fmt.Errorf("%s: %w", key, err)
+	}
+	return limit, nil
+}
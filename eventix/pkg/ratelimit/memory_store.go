@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often full buckets are dropped; a full bucket
+// behaves exactly like a missing one.
+const memorySweepInterval = time.Minute
+
+// MemoryStore keeps buckets in process memory. Limits are per instance, so it
+// only fits deployments with a single one.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.capacity()), updated: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := take(bucket.tokens, bucket.updated, now, limit)
+	bucket.tokens = tokens
+	bucket.updated = now
+	bucket.fullAt = now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
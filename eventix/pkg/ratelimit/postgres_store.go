@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresPruneInterval is how often each instance deletes full buckets.
+const postgresPruneInterval = 10 * time.Minute
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// instance shares them. Each take locks its bucket row, so concurrent requests
+// for one key are counted one after the other. Instances should keep their
+// clocks in sync, as bucket refills use the caller's time.
+type PostgresStore struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+type postgresBucket struct {
+	Key        string    `gorm:"primaryKey;type:varchar(255)"`
+	Tokens     float64   `gorm:"not null"`
+	RefilledAt time.Time `gorm:"not null"`
+	FullAt     time.Time `gorm:"not null;index"`
+}
+
+func (postgresBucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// NewPostgresStore creates the bucket table if needed.
+func NewPostgresStore(db *gorm.DB) (*PostgresStore, error) {
+	if err := db.AutoMigrate(&postgresBucket{}); err != nil {
+		return nil, err
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.pruneIfDue(ctx, now)
+
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A missing bucket is a full one
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&postgresBucket{
+			Key:        key,
+			Tokens:     float64(limit.capacity()),
+			RefilledAt: now,
+			FullAt:     now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var bucket postgresBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).
+			First(&bucket).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, result = take(bucket.Tokens, bucket.RefilledAt, now, limit)
+		return tx.Model(&postgresBucket{}).
+			Where("key = ?", key).
+			Updates(map[string]interface{}{
+				"tokens":      tokens,
+				"refilled_at": now,
+				"full_at":     now.Add(result.Reset),
+			}).Error
+	})
+	return result, err
+}
+
+// pruneIfDue deletes buckets that have refilled completely, at most once per
+// interval and instance.
+func (s *PostgresStore) pruneIfDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < postgresPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	result := s.db.WithContext(ctx).Where("full_at <= ?", now).Delete(&postgresBucket{})
+	if result.Error != nil {
+		log.Printf("[RateLimit] Failed to prune buckets: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[RateLimit] Pruned %d full bucket(s)", result.RowsAffected)
+	}
+}
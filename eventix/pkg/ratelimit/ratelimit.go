@@ -0,0 +1,126 @@
+// Package ratelimit implements token-bucket rate limits with pluggable
+// storage: MemoryStore for a single instance and PostgresStore for limits
+// shared by several instances.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Per on average with bursts of up to Burst
+// requests. The bucket holds Burst tokens and refills continuously.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// Disabled reports whether the limit lets everything through.
+func (l Limit) Disabled() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// Policy describes the limit as a RateLimit-Policy header value, e.g. "10;w=60"
+// or "100;w=3600;burst=20".
+func (l Limit) Policy() string {
+	policy := fmt.Sprintf("%d;w=%d", l.Requests, int(math.Ceil(l.Per.Seconds())))
+	if l.Burst > 0 {
+		policy += fmt.Sprintf(";burst=%d", l.Burst)
+	}
+	return policy
+}
+
+func (l Limit) capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate returns the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// ParseLimit reads limits written as "<requests>/<period>[,<burst>]", e.g.
+// "5/1m" or "100/1h,20". Without a burst the bucket holds <requests> tokens.
+// "off", "0" and an empty value disable the limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" || value == "0" {
+		return Limit{}, nil
+	}
+
+	rate, burst, hasBurst := strings.Cut(value, ",")
+	requests, period, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must look like 10/1m", value)
+	}
+
+	var limit Limit
+	var err error
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || limit.Requests <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid number of requests", value)
+	}
+	if limit.Per, err = time.ParseDuration(strings.TrimSpace(period)); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid period", value)
+	}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("rate limit %q: invalid burst", value)
+		}
+	}
+	return limit, nil
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed bool
+	// Limit is the bucket capacity
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request is allowed; zero when
+	// this one was
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps buckets by key. Take must be atomic per key: concurrent calls
+// for one key may never hand out the same token twice.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// take refills a bucket that held tokens when it was last updated and takes
+// one token from it if it can. It returns the tokens left and the outcome.
+func take(tokens float64, updated, now time.Time, limit Limit) (float64, Result) {
+	capacity := float64(limit.capacity())
+	rate := limit.rate()
+
+	elapsed := now.Sub(updated).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens = math.Min(capacity, tokens+elapsed*rate)
+
+	result := Result{Limit: limit.capacity()}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / rate)
+	return tokens, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	valid := map[string]Limit{
+		"5/1m":          {Requests: 5, Per: time.Minute},
+		"100/1h,20":     {Requests: 100, Per: time.Hour, Burst: 20},
+		" 10 / 30s , 3": {Requests: 10, Per: 30 * time.Second, Burst: 3},
+		"":              {},
+		"off":           {},
+		"0":             {},
+	}
+	for value, want := range valid {
+		got, err := ParseLimit(value)
+		if err != nil {
+			t.Errorf("ParseLimit(%q): %v", value, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseLimit(%q) = %+v, want %+v", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"5", "x/1m", "0/1m", "-1/1m", "5/soon", "5/0s", "5/1m,", "5/1m,0", "5/1m,x"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestLimitDisabled(t *testing.T) {
+	limit, err := ParseLimit("off")
+	if err != nil {
+		t.Fatalf("ParseLimit: %v", err)
+	}
+	if !limit.Disabled() {
+		t.Errorf("%+v is not disabled", limit)
+	}
+
+	limit, err = ParseLimit("5/1m")
+	if err != nil {
+		t.Fatalf("ParseLimit: %v", err)
+	}
+	if limit.Disabled() {
+		t.Errorf("%+v is disabled", limit)
+	}
+	if got, want := limit.Policy(), "5;w=60"; got != want {
+		t.Errorf("Policy() = %q, want %q", got, want)
+	}
+}
+
+func TestTake(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	perSecond := Limit{Requests: 60, Per: time.Minute, Burst: 5}
+
+	tests := []struct {
+		name       string
+		limit      Limit
+		tokens     float64
+		elapsed    time.Duration
+		wantTokens float64
+		want       Result
+	}{
+		{
+			name:       "full bucket",
+			limit:      perSecond,
+			tokens:     5,
+			wantTokens: 4,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 4, Reset: time.Second},
+		},
+		{
+			name:       "empty bucket",
+			limit:      perSecond,
+			tokens:     0,
+			wantTokens: 0,
+			want:       Result{Limit: 5, RetryAfter: time.Second, Reset: 5 * time.Second},
+		},
+		{
+			name:       "partly refilled",
+			limit:      perSecond,
+			tokens:     0,
+			elapsed:    500 * time.Millisecond,
+			wantTokens: 0.5,
+			want:       Result{Limit: 5, RetryAfter: 500 * time.Millisecond, Reset: 4500 * time.Millisecond},
+		},
+		{
+			name:       "refilled",
+			limit:      perSecond,
+			tokens:     0,
+			elapsed:    2 * time.Second,
+			wantTokens: 1,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 1, Reset: 4 * time.Second},
+		},
+		{
+			name:       "refill capped at burst",
+			limit:      perSecond,
+			tokens:     3,
+			elapsed:    time.Hour,
+			wantTokens: 4,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 4, Reset: time.Second},
+		},
+		{
+			name:       "clock moved back",
+			limit:      perSecond,
+			tokens:     2,
+			elapsed:    -time.Minute,
+			wantTokens: 1,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 1, Reset: 4 * time.Second},
+		},
+		{
+			name:       "capacity defaults to requests",
+			limit:      Limit{Requests: 2, Per: time.Second},
+			tokens:     2,
+			wantTokens: 1,
+			want:       Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, got := take(tt.tokens, base, base.Add(tt.elapsed), tt.limit)
+			if tokens != tt.wantTokens {
+				t.Errorf("tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if got != tt.want {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 1, Per: time.Minute}
+	base := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+
+	takeAt := func(key string, offset time.Duration) Result {
+		t.Helper()
+		result, err := store.Take(ctx, key, limit, base.Add(offset))
+		if err != nil {
+			t.Fatalf("Take(%q): %v", key, err)
+		}
+		return result
+	}
+
+	if !takeAt("a", 0).Allowed {
+		t.Fatal("first request was rejected")
+	}
+	if result := takeAt("a", 0); result.Allowed || result.RetryAfter != time.Minute {
+		t.Fatalf("second request = %+v, want rejected for a minute", result)
+	}
+	if !takeAt("other", 0).Allowed {
+		t.Fatal("request for another key was rejected")
+	}
+	if !takeAt("a", time.Minute).Allowed {
+		t.Fatal("request after the refill was rejected")
+	}
+
+	// The next sweep drops buckets that are full again and keeps the others
+	takeAt("c", 90*time.Second)
+	takeAt("b", 121*time.Second)
+	if _, ok := store.buckets["a"]; ok {
+		t.Error("full bucket a was kept")
+	}
+	if _, ok := store.buckets["other"]; ok {
+		t.Error("full bucket other was kept")
+	}
+	if _, ok := store.buckets["c"]; !ok {
+		t.Error("refilling bucket c was dropped")
+	}
+}
@@ -0,0 +1,112 @@
+package ticketdoc
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// QRSize is the edge length in pixels of rendered QR codes.
+	QRSize = 512
+
+	pdfQRSize = 90.0 // mm
+)
+
+// Ticket is one admission printed on its own page.
+type Ticket struct {
+	Code       string
+	HolderName string
+	TierName   string
+	// Seat is the seat label of reserved-seating tickets, empty otherwise
+	Seat string
+}
+
+// Order is everything printed on an order's tickets.
+type Order struct {
+	OrderID       uint
+	EventTitle    string
+	EventDate     time.Time
+	EventLocation string
+	Tickets       []Ticket
+}
+
+// QRCode renders a ticket code as a PNG image. Medium error correction keeps
+// signed codes scannable from a cracked phone screen or a creased printout.
+func QRCode(code string) ([]byte, error) {
+	return qrcode.Encode(code, qrcode.Medium, QRSize)
+}
+
+// FileName is the name downloads and email attachments of the order's tickets
+// are offered under.
+func FileName(orderID uint) string {
+	return fmt.Sprintf("eventix-order-%d.pdf", orderID)
+}
+
+// PDF renders the order as an A4 document with one ticket per page, ready to
+// print or show at the gate.
+func PDF(order Order) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Tickets for %s", order.EventTitle), true)
+	pdf.SetCreator("Eventix", true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(false, 20)
+
+	// Core fonts are cp1252; translate so accented names print correctly
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 40
+
+	for i, ticket := range order.Tickets {
+		png, err := QRCode(ticket.Code)
+		if err != nil {
+			return nil, err
+		}
+		imageName := fmt.Sprintf("ticket-%d", i)
+		pdf.RegisterImageOptionsReader(imageName, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+
+		pdf.AddPage()
+
+		pdf.SetFont("Helvetica", "B", 22)
+		pdf.MultiCell(contentWidth, 10, tr(order.EventTitle), "", "L", false)
+		pdf.Ln(2)
+
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.CellFormat(contentWidth, 7, tr(order.EventDate.Format("Monday, 02 January 2006 15:04 MST")), "", 1, "L", false, 0, "")
+		pdf.MultiCell(contentWidth, 7, tr(order.EventLocation), "", "L", false)
+		pdf.Ln(6)
+
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(contentWidth, 8, tr(ticket.HolderName), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 12)
+		if ticket.TierName != "" {
+			pdf.CellFormat(contentWidth, 7, tr(ticket.TierName), "", 1, "L", false, 0, "")
+		}
+		if ticket.Seat != "" {
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(contentWidth, 7, tr(ticket.Seat), "", 1, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 12)
+		}
+		pdf.CellFormat(contentWidth, 7, fmt.Sprintf("Order #%d - Ticket %d of %d", order.OrderID, i+1, len(order.Tickets)), "", 1, "L", false, 0, "")
+		pdf.Ln(8)
+
+		pdf.ImageOptions(imageName, (pageWidth-pdfQRSize)/2, pdf.GetY(), pdfQRSize, pdfQRSize, true, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		pdf.Ln(4)
+
+		pdf.SetFont("Courier", "", 8)
+		pdf.MultiCell(contentWidth, 4, ticket.Code, "", "C", false)
+		pdf.Ln(6)
+
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.MultiCell(contentWidth, 5, "Show this QR code at the entrance. Each ticket admits one person and can only be scanned once.", "", "C", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsDevelopment reports whether APP_ENV is explicitly "development". Only
+// development may run with ephemeral secrets, so an unset APP_ENV is treated
+// as production.
+func IsDevelopment() bool {
+	return os.Getenv("APP_ENV") == "development"
+}
+
+func GetEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvDuration parses values such as "15m" or "90s", falling back when the
+// variable is unset or malformed.
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid duration %q for %s, using %s", value, key, fallback)
+		return fallback
+	}
+	return d
+}
+
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer %q for %s, using %d", value, key, fallback)
+		return fallback
+	}
+	return n
+}
+
+// GetEnvBool accepts the values understood by strconv.ParseBool, such as
+// "true", "false", "1" or "0".
+func GetEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: invalid boolean %q for %s, using %t", value, key, fallback)
+		return fallback
+	}
+	return b
+}
+
+// GetEnvList splits a comma-separated value, dropping blank entries.
+func GetEnvList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
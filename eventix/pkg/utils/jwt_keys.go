@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownKeyID = errors.New("unknown signing key id")
+
+// SigningKey is a private key used to issue tokens.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Key    crypto.Signer
+}
+
+// VerificationKey is a public key tokens may be verified against.
+type VerificationKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Key    crypto.PublicKey
+}
+
+// KeySet holds the key new tokens are signed with plus every public key that
+// is still accepted. Rotating means adding the new key, switching the signing
+// key to it, and dropping the old public key once its tokens have expired.
+type KeySet struct {
+	signing      *SigningKey
+	verification map[string]*VerificationKey
+	order        []string
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	tokenKeysOnce sync.Once
+	tokenKeys     *KeySet
+	tokenKeysErr  error
+)
+
+// TokenKeys returns the process-wide key set, loading it from the environment
+// on first use:
+//   - JWT_SIGNING_KEY_FILE: PEM private key (RSA or Ed25519) used to sign tokens
+//   - JWT_VERIFICATION_KEY_FILES: comma-separated PEM public keys that are
+//     still accepted, e.g. the previous signing key during a rotation
+//
+// Each key's kid is a thumbprint of its public key, so a key keeps the same
+// kid whether it is loaded as the signing key or as a verification key.
+//
+// Without a signing key file an ephemeral Ed25519 key is generated in
+// development, so tokens do not survive a restart. Elsewhere a missing key file
+// is an error: every restart would log everyone out and replicas would reject
+// each other's tokens.
+func TokenKeys() (*KeySet, error) {
+	tokenKeysOnce.Do(func() {
+		tokenKeys, tokenKeysErr = loadKeySetFromEnv()
+	})
+	return tokenKeys, tokenKeysErr
+}
+
+func loadKeySetFromEnv() (*KeySet, error) {
+	ks := &KeySet{verification: make(map[string]*VerificationKey)}
+
+	signingFile := os.Getenv("JWT_SIGNING_KEY_FILE")
+	if signingFile == "" {
+		if !IsDevelopment() {
+			return nil, errors.New("JWT_SIGNING_KEY_FILE must be set outside development (APP_ENV)")
+		}
+		log.Println("Warning: JWT_SIGNING_KEY_FILE not set, signing tokens with an ephemeral Ed25519 key")
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.setSigningKey(priv); err != nil {
+			return nil, err
+		}
+	} else {
+		priv, err := loadPrivateKey(signingFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.setSigningKey(priv); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, file := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		pub, err := loadPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.addVerificationKey(pub); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return ks, nil
+}
+
+// Signing returns the key new tokens are signed with.
+func (ks *KeySet) Signing() *SigningKey {
+	return ks.signing
+}
+
+// Lookup returns the verification key for a kid.
+func (ks *KeySet) Lookup(kid string) (*VerificationKey, error) {
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
+}
+
+// JWKS returns every accepted public key, signing key first.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		set.Keys = append(set.Keys, toJWK(ks.verification[kid]))
+	}
+	return set
+}
+
+func (ks *KeySet) setSigningKey(priv crypto.Signer) error {
+	method, err := signingMethodFor(priv.Public())
+	if err != nil {
+		return err
+	}
+
+	ks.signing = &SigningKey{ID: keyThumbprint(priv.Public()), Method: method, Key: priv}
+	return ks.addVerificationKey(priv.Public())
+}
+
+// addVerificationKey accepts tokens signed by pub. Listing the current signing
+// key again is harmless and ignored.
+func (ks *KeySet) addVerificationKey(pub crypto.PublicKey) error {
+	method, err := signingMethodFor(pub)
+	if err != nil {
+		return err
+	}
+	kid := keyThumbprint(pub)
+	if _, exists := ks.verification[kid]; exists {
+		return nil
+	}
+
+	ks.verification[kid] = &VerificationKey{ID: kid, Method: method, Key: pub}
+	ks.order = append(ks.order, kid)
+	return nil
+}
+
+func signingMethodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", pub)
+	}
+}
+
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported private key type %T", path, key)
+	}
+	return signer, nil
+}
+
+// loadPublicKey also accepts a private key file and uses its public half, so
+// the previous signing key can be listed as-is during a rotation.
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		priv, err := loadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Public(), nil
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+	return block, nil
+}
+
+// keyThumbprint derives a stable kid from the public key. Callers have
+// already checked the key type, so marshalling cannot fail.
+func keyThumbprint(pub crypto.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func toJWK(key *VerificationKey) JWK {
+	jwk := JWK{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch pub := key.Key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
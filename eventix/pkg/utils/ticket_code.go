@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SignedTicketCodePrefix marks ticket codes that can be verified offline.
+// Codes without it predate signing and can only be checked online.
+const SignedTicketCodePrefix = "TKT1."
+
+const minTicketSeedLength = 32
+
+var ErrInvalidTicketCode = errors.New("invalid ticket code")
+
+// TicketClaims is what a signed ticket code vouches for.
+type TicketClaims struct {
+	TicketID uint
+	EventID  uint
+	HolderID uint
+	IssuedAt time.Time
+}
+
+// TicketSigner derives one Ed25519 key pair per event from a master seed, so
+// scanners only ever hold the public keys of the events they work and no key
+// material has to be stored per event.
+type TicketSigner struct {
+	seed []byte
+}
+
+var (
+	ticketSignerOnce sync.Once
+	ticketSigner     *TicketSigner
+	ticketSignerErr  error
+)
+
+// TicketCodeSigner returns the process-wide signer, loading it from the
+// environment on first use:
+//   - TICKET_SIGNING_SEED: hex-encoded secret of at least 32 bytes
+//
+// Changing the seed changes every event key, so codes issued before can no
+// longer be verified offline. Without a seed an ephemeral one is generated in
+// development; elsewhere a missing seed is an error, since every restart and
+// replica would derive different keys.
+func TicketCodeSigner() (*TicketSigner, error) {
+	ticketSignerOnce.Do(func() {
+		ticketSigner, ticketSignerErr = loadTicketSignerFromEnv()
+	})
+	return ticketSigner, ticketSignerErr
+}
+
+func loadTicketSignerFromEnv() (*TicketSigner, error) {
+	value := os.Getenv("TICKET_SIGNING_SEED")
+	if value == "" {
+		if !IsDevelopment() {
+			return nil, errors.New("TICKET_SIGNING_SEED must be set outside development (APP_ENV)")
+		}
+		log.Println("Warning: TICKET_SIGNING_SEED not set, signing ticket codes with an ephemeral seed")
+		seed := make([]byte, minTicketSeedLength)
+		if _, err := rand.Read(seed); err != nil {
+			return nil, err
+		}
+		return &TicketSigner{seed: seed}, nil
+	}
+
+	seed, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("TICKET_SIGNING_SEED must be hex encoded: %w", err)
+	}
+	if len(seed) < minTicketSeedLength {
+		return nil, fmt.Errorf("TICKET_SIGNING_SEED must be at least %d bytes", minTicketSeedLength)
+	}
+	return &TicketSigner{seed: seed}, nil
+}
+
+// SignTicketCode issues a signed code for the ticket described by claims.
+func SignTicketCode(claims TicketClaims) (string, error) {
+	signer, err := TicketCodeSigner()
+	if err != nil {
+		return "", err
+	}
+	return signer.Sign(claims), nil
+}
+
+// Sign encodes claims as TKT1.<payload>.<signature>, both base64url. The
+// payload is the ticket ID, event ID, holder ID and issue time (unix seconds)
+// as consecutive unsigned varints, which keeps codes small enough for QR codes.
+func (s *TicketSigner) Sign(claims TicketClaims) string {
+	payload := make([]byte, 0, 4*binary.MaxVarintLen64)
+	payload = binary.AppendUvarint(payload, uint64(claims.TicketID))
+	payload = binary.AppendUvarint(payload, uint64(claims.EventID))
+	payload = binary.AppendUvarint(payload, uint64(claims.HolderID))
+	payload = binary.AppendUvarint(payload, uint64(claims.IssuedAt.Unix()))
+
+	signature := ed25519.Sign(s.eventKey(claims.EventID), payload)
+	return SignedTicketCodePrefix +
+		base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// PublicKey returns the key scanners verify codes of the event with.
+func (s *TicketSigner) PublicKey(eventID uint) JWK {
+	pub := s.eventKey(eventID).Public()
+	return toJWK(&VerificationKey{
+		ID:     keyThumbprint(pub),
+		Method: jwt.SigningMethodEdDSA,
+		Key:    pub,
+	})
+}
+
+func (s *TicketSigner) eventKey(eventID uint) ed25519.PrivateKey {
+	mac := hmac.New(sha256.New, s.seed)
+	mac.Write([]byte("eventix-ticket-key:" + strconv.FormatUint(uint64(eventID), 10)))
+	return ed25519.NewKeyFromSeed(mac.Sum(nil))
+}
+
+// VerifyTicketCode checks a signed code against an event public key and
+// returns its claims. It needs no database, which is what scanners rely on
+// when the venue is offline; they still have to consult the revocation list.
+func VerifyTicketCode(code string, pub ed25519.PublicKey) (*TicketClaims, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("ticket verification key must be %d bytes, got %d", ed25519.PublicKeySize, len(pub))
+	}
+	if !strings.HasPrefix(code, SignedTicketCodePrefix) {
+		return nil, ErrInvalidTicketCode
+	}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(strings.TrimPrefix(code, SignedTicketCodePrefix), ".")
+	if !ok {
+		return nil, ErrInvalidTicketCode
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	if !ed25519.Verify(pub, payload, signature) {
+		return nil, ErrInvalidTicketCode
+	}
+
+	fields := make([]uint64, 4)
+	for i := range fields {
+		value, n := binary.Uvarint(payload)
+		if n <= 0 {
+			return nil, ErrInvalidTicketCode
+		}
+		fields[i] = value
+		payload = payload[n:]
+	}
+	if len(payload) != 0 {
+		return nil, ErrInvalidTicketCode
+	}
+
+	return &TicketClaims{
+		TicketID: uint(fields[0]),
+		EventID:  uint(fields[1]),
+		HolderID: uint(fields[2]),
+		IssuedAt: time.Unix(int64(fields[3]), 0),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTicketCodeRoundTrip(t *testing.T) {
+	signer := &TicketSigner{seed: []byte(strings.Repeat("s", minTicketSeedLength))}
+	claims := TicketClaims{
+		TicketID: 42,
+		EventID:  7,
+		HolderID: 3,
+		IssuedAt: time.Unix(1700000000, 0),
+	}
+	code := signer.Sign(claims)
+	pub := signer.eventKey(claims.EventID).Public().(ed25519.PublicKey)
+
+	got, err := VerifyTicketCode(code, pub)
+	if err != nil {
+		t.Fatalf("VerifyTicketCode: %v", err)
+	}
+	if *got != claims {
+		t.Fatalf("claims = %+v, want %+v", *got, claims)
+	}
+
+	payload, signature, _ := strings.Cut(strings.TrimPrefix(code, SignedTicketCodePrefix), ".")
+	otherEvent := signer.Sign(TicketClaims{TicketID: 42, EventID: 8, HolderID: 3, IssuedAt: claims.IssuedAt})
+	otherPayload, _, _ := strings.Cut(strings.TrimPrefix(otherEvent, SignedTicketCodePrefix), ".")
+
+	rejected := map[string]string{
+		"tampered payload":   SignedTicketCodePrefix + otherPayload + "." + signature,
+		"tampered signature": SignedTicketCodePrefix + payload + "." + flipFirstChar(signature),
+		"missing prefix":     strings.TrimPrefix(code, SignedTicketCodePrefix),
+		"missing signature":  SignedTicketCodePrefix + payload,
+		"unsigned code":      "TKT-1234567890",
+	}
+	for name, code := range rejected {
+		if _, err := VerifyTicketCode(code, pub); !errors.Is(err, ErrInvalidTicketCode) {
+			t.Errorf("%s: err = %v, want ErrInvalidTicketCode", name, err)
+		}
+	}
+
+	otherKey := signer.eventKey(claims.EventID + 1).Public().(ed25519.PublicKey)
+	if _, err := VerifyTicketCode(code, otherKey); !errors.Is(err, ErrInvalidTicketCode) {
+		t.Errorf("other event's key: err = %v, want ErrInvalidTicketCode", err)
+	}
+}
+
+func TestVerifyTicketCodeRejectsMalformedKey(t *testing.T) {
+	signer := &TicketSigner{seed: []byte(strings.Repeat("s", minTicketSeedLength))}
+	code := signer.Sign(TicketClaims{TicketID: 1, EventID: 1, HolderID: 1, IssuedAt: time.Unix(1700000000, 0)})
+
+	for _, pub := range []ed25519.PublicKey{nil, make(ed25519.PublicKey, ed25519.PublicKeySize-1)} {
+		if _, err := VerifyTicketCode(code, pub); err == nil {
+			t.Errorf("key of %d bytes was accepted", len(pub))
+		}
+	}
+}
+
+// flipFirstChar changes the first base64 character, whose bits all belong to
+// the encoded data (the last one may only carry padding bits).
+func flipFirstChar(s string) string {
+	replacement := "A"
+	if s[0] == 'A' {
+		replacement = "B"
+	}
+	return replacement + s[1:]
+}
@@ -1,46 +1,70 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-const defaultJWTSecret = "default-dev-secret-key-do-not-use-in-production"
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
 
 type TokenClaims struct {
-	UserID uint   `json:"user_id"`
-	Role   string `json:"role"`
+	UserID      uint     `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
 	jwt.RegisteredClaims
 }
 
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return defaultJWTSecret
-	}
-	return secret
+// AccessTokenTTL is how long an access token stays valid. Keep it short:
+// revocation relies on the jti denylist only until the token expires.
+func AccessTokenTTL() time.Duration {
+	return GetEnvDuration("JWT_ACCESS_TTL", defaultAccessTokenTTL)
+}
+
+func RefreshTokenTTL() time.Duration {
+	return GetEnvDuration("JWT_REFRESH_TTL", defaultRefreshTokenTTL)
 }
 
-func GenerateToken(userID uint, role string) (string, error) {
-	// Expired 24 Hours
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken issues an access token carrying the user's roles and the
+// permissions they grant, so changes apply once the token is refreshed.
+func GenerateToken(userID uint, roles, permissions []string) (string, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL())
+
+	jti, err := GenerateTokenID()
+	if err != nil {
+		return "", err
+	}
 
 	claims := &TokenClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Roles:       roles,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "eventix",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	keys, err := TokenKeys()
+	if err != nil {
+		return "", err
+	}
+	signing := keys.Signing()
 
-	tokenString, err := token.SignedString([]byte(getJWTSecret()))
+	token := jwt.NewWithClaims(signing.Method, claims)
+	token.Header["kid"] = signing.ID
+
+	tokenString, err := token.SignedString(signing.Key)
 	if err != nil {
 		return "", err
 	}
@@ -48,13 +72,25 @@ func GenerateToken(userID uint, role string) (string, error) {
 	return tokenString, nil
 }
 
+// ValidateToken verifies a token against the key named by its kid header. The
+// algorithm must match the key type so a token cannot pick its own method.
 func ValidateToken(tokenString string) (*TokenClaims, error) {
+	keys, err := TokenKeys()
+	if err != nil {
+		return nil, err
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.Lookup(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(getJWTSecret()), nil
-	})
+		return key.Key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -67,3 +103,29 @@ func ValidateToken(tokenString string) (*TokenClaims, error) {
 
 	return nil, errors.New("invalid token claims")
 }
+
+// GenerateRefreshToken returns an opaque random refresh token. Only its hash
+// is stored server-side.
+func GenerateRefreshToken() (string, error) {
+	return randomToken(32)
+}
+
+// GenerateTokenID returns a random identifier for a JWT (jti) or a refresh
+// token family.
+func GenerateTokenID() (string, error) {
+	return randomToken(16)
+}
+
+// HashToken returns the hex SHA-256 of an opaque token for storage and lookup.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// PurgeFunc deletes rows that are no longer needed and returns how many were
+// removed.
+type PurgeFunc func() (int64, error)
+
+type cleanupTask struct {
+	name  string
+	purge PurgeFunc
+}
+
+// Cleaner periodically prunes tables that only grow, such as the token tables
+// and processed outbox messages, until its context is cancelled.
+type Cleaner struct {
+	tasks    []cleanupTask
+	interval time.Duration
+	wg       sync.WaitGroup
+}
+
+func NewCleaner(interval time.Duration) *Cleaner {
+	return &Cleaner{
+		interval: interval,
+	}
+}
+
+// Add registers a purge run on every pass; name describes what it removes in
+// the logs, e.g. "expired token(s)". Tasks must be added before Start.
+func (c *Cleaner) Add(name string, purge PurgeFunc) {
+	c.tasks = append(c.tasks, cleanupTask{name: name, purge: purge})
+}
+
+func (c *Cleaner) Start(ctx context.Context) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		log.Printf("[Cleaner] Started, purging every %s", c.interval)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("[Cleaner] Stopped")
+				return
+			case <-ticker.C:
+				c.purge()
+			}
+		}
+	}()
+}
+
+// Wait blocks until the cleaner has finished its current pass and stopped.
+func (c *Cleaner) Wait() {
+	c.wg.Wait()
+}
+
+func (c *Cleaner) purge() {
+	for _, task := range c.tasks {
+		purged, err := task.purge()
+		if err != nil {
+			log.Printf("[Cleaner] Failed to purge %s: %v", task.name, err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("[Cleaner] Purged %d %s", purged, task.name)
+		}
+	}
+}
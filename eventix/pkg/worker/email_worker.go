@@ -1,33 +1,128 @@
 package worker
 
 import (
+	"encoding/json"
 	"log"
-	"time"
 
 	"eventix/internal/entity"
+	"eventix/pkg/mailer"
+	"eventix/pkg/ticketdoc"
 )
 
 type EmailJob struct {
 	Order entity.Order
 	Email string
+	Name  string
 }
 
-func StartEmailWorker(jobChan <-chan EmailJob) {
-	go func() {
-		log.Println("[EmailWorker] Started and listening for jobs...")
-		for job := range jobChan {
-			processEmailJob(job)
+// NewEmailJobHandler returns the outbox handler that renders and sends order
+// confirmation emails through m.
+func NewEmailJobHandler(m mailer.Mailer) OutboxHandler {
+	return func(msg entity.OutboxMessage) error {
+		var job EmailJob
+		if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+			return err
 		}
-		log.Println("[EmailWorker] Channel closed, worker stopped")
-	}()
+		return processEmailJob(m, job)
+	}
 }
 
-func processEmailJob(job EmailJob) {
-	log.Printf("[EmailWorker] Simulating email sending for Order ID %d to %s...", job.Order.ID, job.Email)
-	log.Printf("[EmailWorker] Order Details - Event: %s, Quantity: %d, Total: $%.2f",
-		job.Order.Event.Title, job.Order.Quantity, job.Order.TotalAmount)
+func processEmailJob(m mailer.Mailer, job EmailJob) error {
+	codes := make([]string, len(job.Order.Tickets))
+	for i, ticket := range job.Order.Tickets {
+		codes[i] = ticket.TicketCode
+	}
 
-	time.Sleep(2 * time.Second)
+	message, err := mailer.OrderConfirmation(job.Email, mailer.OrderConfirmationData{
+		Name:          job.Name,
+		OrderID:       job.Order.ID,
+		EventTitle:    job.Order.Event.Title,
+		EventDate:     job.Order.Event.Date,
+		EventLocation: job.Order.Event.Location,
+		Quantity:      job.Order.Quantity,
+		TotalAmount:   job.Order.TotalAmount,
+		TicketCodes:   codes,
+	})
+	if err != nil {
+		return err
+	}
 
-	log.Printf("[EmailWorker] Email sent successfully for Order ID %d", job.Order.ID)
+	// Attach the printable tickets; every ticket is still held by the buyer
+	if len(job.Order.Tickets) > 0 {
+		pdf, err := ticketdoc.PDF(confirmationDocument(job))
+		if err != nil {
+			return err
+		}
+		message.Attachments = append(message.Attachments, mailer.Attachment{
+			FileName:    ticketdoc.FileName(job.Order.ID),
+			ContentType: "application/pdf",
+			Data:        pdf,
+		})
+	}
+
+	if err := m.Send(message); err != nil {
+		return err
+	}
+
+	log.Printf("[EmailWorker] Confirmation for Order ID %d sent to %s", job.Order.ID, job.Email)
+	return nil
+}
+
+func confirmationDocument(job EmailJob) ticketdoc.Order {
+	tierNames := make(map[uint]string, len(job.Order.Items))
+	for _, item := range job.Order.Items {
+		tierNames[item.TicketTypeID] = item.TicketTypeName
+	}
+
+	doc := ticketdoc.Order{
+		OrderID:       job.Order.ID,
+		EventTitle:    job.Order.Event.Title,
+		EventDate:     job.Order.Event.Date,
+		EventLocation: job.Order.Event.Location,
+		Tickets:       make([]ticketdoc.Ticket, len(job.Order.Tickets)),
+	}
+	for i, ticket := range job.Order.Tickets {
+		doc.Tickets[i] = ticketdoc.Ticket{Code: ticket.TicketCode, HolderName: job.Name}
+		if ticket.TicketTypeID != nil {
+			doc.Tickets[i].TierName = tierNames[*ticket.TicketTypeID]
+		}
+		if ticket.Seat != nil {
+			doc.Tickets[i].Seat = ticket.Seat.Label
+		}
+	}
+	return doc
+}
+
+type TicketTransferJob struct {
+	Transfer   entity.TicketTransfer
+	SenderName string
+}
+
+// NewTicketTransferJobHandler returns the outbox handler that tells the
+// recipient of a ticket transfer how to accept it.
+func NewTicketTransferJobHandler(m mailer.Mailer) OutboxHandler {
+	return func(msg entity.OutboxMessage) error {
+		var job TicketTransferJob
+		if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+			return err
+		}
+
+		transfer := job.Transfer
+		message, err := mailer.TicketTransfer(transfer.ToEmail, mailer.TicketTransferData{
+			SenderName:    job.SenderName,
+			TransferID:    transfer.ID,
+			EventTitle:    transfer.Event.Title,
+			EventDate:     transfer.Event.Date,
+			EventLocation: transfer.Event.Location,
+		})
+		if err != nil {
+			return err
+		}
+		if err := m.Send(message); err != nil {
+			return err
+		}
+
+		log.Printf("[EmailWorker] Transfer %d notification sent to %s", transfer.ID, transfer.ToEmail)
+		return nil
+	}
 }
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// HoldExpirer is implemented by the hold service; it returns the tickets of
+// holds that ran out and reports how many were expired.
+type HoldExpirer interface {
+	ExpireHolds() (int, error)
+}
+
+// HoldReaper periodically expires holds until its context is cancelled.
+type HoldReaper struct {
+	expirer  HoldExpirer
+	interval time.Duration
+	wg       sync.WaitGroup
+}
+
+func NewHoldReaper(expirer HoldExpirer, interval time.Duration) *HoldReaper {
+	return &HoldReaper{
+		expirer:  expirer,
+		interval: interval,
+	}
+}
+
+func (r *HoldReaper) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		log.Printf("[HoldReaper] Started, checking for expired holds every %s", r.interval)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("[HoldReaper] Stopped")
+				return
+			case <-ticker.C:
+				r.reap()
+			}
+		}
+	}()
+}
+
+// Wait blocks until the reaper has finished its current pass and stopped.
+func (r *HoldReaper) Wait() {
+	r.wg.Wait()
+}
+
+func (r *HoldReaper) reap() {
+	expired, err := r.expirer.ExpireHolds()
+	if err != nil {
+		log.Printf("[HoldReaper] Failed to expire holds: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("[HoldReaper] Expired %d hold(s)", expired)
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// OrderExpirer is implemented by the order service; it cancels unpaid orders
+// whose payment window has passed and returns how many were expired.
+type OrderExpirer interface {
+	ExpirePendingOrders() (int, error)
+}
+
+// OrderReaper periodically expires unpaid orders until its context is cancelled.
+type OrderReaper struct {
+	expirer  OrderExpirer
+	interval time.Duration
+	wg       sync.WaitGroup
+}
+
+func NewOrderReaper(expirer OrderExpirer, interval time.Duration) *OrderReaper {
+	return &OrderReaper{
+		expirer:  expirer,
+		interval: interval,
+	}
+}
+
+func (r *OrderReaper) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		log.Printf("[OrderReaper] Started, checking for expired orders every %s", r.interval)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("[OrderReaper] Stopped")
+				return
+			case <-ticker.C:
+				r.reap()
+			}
+		}
+	}()
+}
+
+// Wait blocks until the reaper has finished its current pass and stopped.
+func (r *OrderReaper) Wait() {
+	r.wg.Wait()
+}
+
+func (r *OrderReaper) reap() {
+	expired, err := r.expirer.ExpirePendingOrders()
+	if err != nil {
+		log.Printf("[OrderReaper] Failed to expire pending orders: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("[OrderReaper] Expired %d unpaid order(s)", expired)
+	}
+}
@@ -0,0 +1,225 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"eventix/internal/entity"
+)
+
+// OutboxStore is the persistence the dispatcher needs; it is implemented by
+// repository.OutboxRepository.
+type OutboxStore interface {
+	Claim(limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	Renew(id uint, token string, lease time.Duration) error
+	MarkDone(id uint, token string) error
+	MarkFailed(id uint, token, reason string, retryAt time.Time) error
+	MarkDead(id uint, token, reason string) error
+	Release(ids []uint, token string) error
+}
+
+// OutboxHandler delivers a single message. Returning an error schedules the
+// message for another attempt, so handlers must tolerate duplicates.
+type OutboxHandler func(msg entity.OutboxMessage) error
+
+type OutboxDispatcherConfig struct {
+	Workers       int
+	PollInterval  time.Duration
+	BatchSize     int
+	Lease         time.Duration
+	MaxAttempts   int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// DispatcherMetrics are cumulative counters for this process.
+type DispatcherMetrics struct {
+	Processed    uint64
+	Failed       uint64
+	Retried      uint64
+	DeadLettered uint64
+}
+
+// OutboxDispatcher runs a pool of workers that poll the outbox table and hand
+// each claimed message to the handler registered for its topic, giving
+// at-least-once delivery across restarts and multiple instances. Failed
+// messages are retried with exponential backoff until MaxAttempts, after which
+// they are moved to the dead-letter state for an admin to inspect.
+type OutboxDispatcher struct {
+	store    OutboxStore
+	config   OutboxDispatcherConfig
+	handlers map[string]OutboxHandler
+	wg       sync.WaitGroup
+
+	processed    atomic.Uint64
+	failed       atomic.Uint64
+	retried      atomic.Uint64
+	deadLettered atomic.Uint64
+}
+
+func NewOutboxDispatcher(store OutboxStore, config OutboxDispatcherConfig) *OutboxDispatcher {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &OutboxDispatcher{
+		store:    store,
+		config:   config,
+		handlers: make(map[string]OutboxHandler),
+	}
+}
+
+func (d *OutboxDispatcher) Handle(topic string, handler OutboxHandler) {
+	d.handlers[topic] = handler
+}
+
+// Start launches the worker pool. Workers stop claiming new messages once ctx
+// is cancelled; use Wait to block until in-flight messages are finished.
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	log.Printf("[OutboxDispatcher] Starting %d worker(s), polling every %s", d.config.Workers, d.config.PollInterval)
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go func(id int) {
+			defer d.wg.Done()
+			d.runWorker(ctx, id)
+		}(i + 1)
+	}
+}
+
+// Wait blocks until every worker has stopped.
+func (d *OutboxDispatcher) Wait() {
+	d.wg.Wait()
+	log.Println("[OutboxDispatcher] All workers stopped")
+}
+
+func (d *OutboxDispatcher) Workers() int {
+	return d.config.Workers
+}
+
+func (d *OutboxDispatcher) Metrics() DispatcherMetrics {
+	return DispatcherMetrics{
+		Processed:    d.processed.Load(),
+		Failed:       d.failed.Load(),
+		Retried:      d.retried.Load(),
+		DeadLettered: d.deadLettered.Load(),
+	}
+}
+
+func (d *OutboxDispatcher) runWorker(ctx context.Context, id int) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep draining while there is work instead of waiting a full interval
+			for ctx.Err() == nil {
+				if d.dispatchBatch(ctx, id) < d.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// dispatchBatch claims and processes one batch, returning how many messages it
+// claimed. Each message's lease is renewed right before it is processed, and a
+// message whose claim was lost meanwhile is skipped, so a slow batch cannot
+// get its tail sent twice. If ctx is cancelled mid-batch, the unprocessed
+// messages are handed back to the queue right away instead of waiting for
+// their lease to expire.
+func (d *OutboxDispatcher) dispatchBatch(ctx context.Context, workerID int) int {
+	messages, err := d.store.Claim(d.config.BatchSize, d.config.Lease)
+	if err != nil {
+		log.Printf("[OutboxDispatcher] Worker %d failed to claim messages: %v", workerID, err)
+		return 0
+	}
+
+	for i, msg := range messages {
+		if ctx.Err() != nil {
+			d.release(messages[i:])
+			break
+		}
+		if err := d.store.Renew(msg.ID, msg.ClaimToken, d.config.Lease); err != nil {
+			log.Printf("[OutboxDispatcher] Worker %d skipping message %d, its lease could not be renewed: %v", workerID, msg.ID, err)
+			continue
+		}
+		d.dispatch(msg)
+	}
+	return len(messages)
+}
+
+func (d *OutboxDispatcher) release(messages []entity.OutboxMessage) {
+	ids := make([]uint, len(messages))
+	for i := range messages {
+		ids[i] = messages[i].ID
+	}
+	if err := d.store.Release(ids, messages[0].ClaimToken); err != nil {
+		log.Printf("[OutboxDispatcher] Failed to release %d message(s): %v", len(ids), err)
+		return
+	}
+	log.Printf("[OutboxDispatcher] Released %d unprocessed message(s) back to the queue", len(ids))
+}
+
+func (d *OutboxDispatcher) dispatch(msg entity.OutboxMessage) {
+	handler, ok := d.handlers[msg.Topic]
+	if !ok {
+		log.Printf("[OutboxDispatcher] No handler for topic %q (message %d)", msg.Topic, msg.ID)
+		d.fail(msg, "no handler registered for topic")
+		return
+	}
+
+	if err := handler(msg); err != nil {
+		log.Printf("[OutboxDispatcher] Message %d (%s) failed on attempt %d/%d: %v",
+			msg.ID, msg.Topic, msg.Attempts, d.config.MaxAttempts, err)
+		d.fail(msg, err.Error())
+		return
+	}
+
+	d.processed.Add(1)
+	if err := d.store.MarkDone(msg.ID, msg.ClaimToken); err != nil {
+		log.Printf("[OutboxDispatcher] Failed to mark message %d as done: %v", msg.ID, err)
+	}
+}
+
+func (d *OutboxDispatcher) fail(msg entity.OutboxMessage, reason string) {
+	d.failed.Add(1)
+
+	if msg.Attempts >= d.config.MaxAttempts {
+		d.deadLettered.Add(1)
+		log.Printf("[OutboxDispatcher] Message %d (%s) moved to dead letters after %d attempts", msg.ID, msg.Topic, msg.Attempts)
+		if err := d.store.MarkDead(msg.ID, msg.ClaimToken, reason); err != nil {
+			log.Printf("[OutboxDispatcher] Failed to dead-letter message %d: %v", msg.ID, err)
+		}
+		return
+	}
+
+	d.retried.Add(1)
+	if err := d.store.MarkFailed(msg.ID, msg.ClaimToken, reason, time.Now().Add(d.backoff(msg.Attempts))); err != nil {
+		log.Printf("[OutboxDispatcher] Failed to reschedule message %d: %v", msg.ID, err)
+	}
+}
+
+// backoff doubles the retry delay with every attempt, capped at MaxRetryDelay,
+// and adds up to 20% jitter so failing messages do not retry in lockstep.
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.RetryDelay
+	for i := 1; i < attempts && delay < d.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if d.config.MaxRetryDelay > 0 && delay > d.config.MaxRetryDelay {
+		delay = d.config.MaxRetryDelay
+	}
+	if delay > 0 {
+		delay += rand.N(delay/5 + 1)
+	}
+	return delay
+}
@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"encoding/json"
+
+	"eventix/internal/entity"
+)
+
+// RefundProcessor is implemented by the refund service; it pays a recorded
+// refund back through the payment provider.
+type RefundProcessor interface {
+	CompleteRefund(refundID uint) error
+}
+
+type RefundJob struct {
+	RefundID uint
+}
+
+// NewRefundJobHandler returns the outbox handler that pays out refunds once
+// the transaction recording them has committed. A failed payout is retried
+// with the outbox backoff and dead-lettered for an admin in the end.
+func NewRefundJobHandler(processor RefundProcessor) OutboxHandler {
+	return func(msg entity.OutboxMessage) error {
+		var job RefundJob
+		if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+			return err
+		}
+		return processor.CompleteRefund(job.RefundID)
+	}
+}
@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"eventix/internal/entity"
+	"eventix/pkg/mailer"
+)
+
+// WaitlistProcessor is implemented by the waitlist service; it expires
+// unclaimed offers and makes new ones, returning how many offers were made.
+type WaitlistProcessor interface {
+	ProcessWaitlists() (int, error)
+}
+
+// WaitlistWorker periodically rolls expired waitlist offers over to the next
+// person in line until its context is cancelled.
+type WaitlistWorker struct {
+	processor WaitlistProcessor
+	interval  time.Duration
+	wg        sync.WaitGroup
+}
+
+func NewWaitlistWorker(processor WaitlistProcessor, interval time.Duration) *WaitlistWorker {
+	return &WaitlistWorker{
+		processor: processor,
+		interval:  interval,
+	}
+}
+
+func (w *WaitlistWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		log.Printf("[WaitlistWorker] Started, checking waitlist offers every %s", w.interval)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("[WaitlistWorker] Stopped")
+				return
+			case <-ticker.C:
+				w.process()
+			}
+		}
+	}()
+}
+
+// Wait blocks until the worker has finished its current pass and stopped.
+func (w *WaitlistWorker) Wait() {
+	w.wg.Wait()
+}
+
+func (w *WaitlistWorker) process() {
+	offered, err := w.processor.ProcessWaitlists()
+	if err != nil {
+		log.Printf("[WaitlistWorker] Failed to process waitlists: %v", err)
+		return
+	}
+	if offered > 0 {
+		log.Printf("[WaitlistWorker] Made %d waitlist offer(s)", offered)
+	}
+}
+
+type WaitlistOfferJob struct {
+	Entry entity.WaitlistEntry
+	Email string
+	Name  string
+}
+
+// NewWaitlistOfferJobHandler returns the outbox handler that tells a user
+// tickets are being held for them.
+func NewWaitlistOfferJobHandler(m mailer.Mailer) OutboxHandler {
+	return func(msg entity.OutboxMessage) error {
+		var job WaitlistOfferJob
+		if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+			return err
+		}
+
+		entry := job.Entry
+		data := mailer.WaitlistOfferData{
+			Name:           job.Name,
+			EntryID:        entry.ID,
+			EventTitle:     entry.Event.Title,
+			EventDate:      entry.Event.Date,
+			EventLocation:  entry.Event.Location,
+			TicketTypeName: entry.TicketType.Name,
+			Quantity:       entry.Quantity,
+		}
+		if entry.OfferExpiresAt != nil {
+			data.ExpiresAt = *entry.OfferExpiresAt
+		}
+
+		message, err := mailer.WaitlistOffer(job.Email, data)
+		if err != nil {
+			return err
+		}
+		if err := m.Send(message); err != nil {
+			return err
+		}
+
+		log.Printf("[WaitlistWorker] Offer for waitlist entry %d sent to %s", entry.ID, job.Email)
+		return nil
+	}
+}